@@ -4,19 +4,23 @@ package autostart
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
-	"syscall"
 
 	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
 	"golang.org/x/sys/windows"
 	"golang.org/x/sys/windows/registry"
 )
 
+// Supervisor 运行时看门狗的最小接口，避免 autostart 直接依赖 watchdog 的实现细节
+type Supervisor interface {
+	SetEnabled(enabled bool)
+}
+
 // Manager 自启动管理器
 type Manager struct {
-	logger types.Logger
+	logger     types.Logger
+	supervisor Supervisor
 }
 
 // NewManager 创建新的自启动管理器
@@ -26,6 +30,18 @@ func NewManager(logger types.Logger) *Manager {
 	}
 }
 
+// SetSupervisor 绑定运行时看门狗，使自启动开关同时控制开机自启动与运行期监督
+func (m *Manager) SetSupervisor(supervisor Supervisor) {
+	m.supervisor = supervisor
+}
+
+// syncSupervisor 将当前自启动开关状态同步给看门狗
+func (m *Manager) syncSupervisor(enabled bool) {
+	if m.supervisor != nil {
+		m.supervisor.SetEnabled(enabled)
+	}
+}
+
 // IsRunningAsAdmin 检查是否以管理员权限运行
 func (m *Manager) IsRunningAsAdmin() bool {
 	var sid *windows.SID
@@ -57,6 +73,8 @@ func (m *Manager) IsRunningAsAdmin() bool {
 
 // SetWindowsAutoStart 设置Windows开机自启动
 func (m *Manager) SetWindowsAutoStart(enable bool) error {
+	defer m.syncSupervisor(enable)
+
 	if enable {
 		// 优先使用注册表，失败后回退到任务计划程序
 		if err := m.setRegistryAutoStart(); err == nil {
@@ -80,56 +98,6 @@ func (m *Manager) SetWindowsAutoStart(enable bool) error {
 	}
 }
 
-// createScheduledTask 创建任务计划程序
-func (m *Manager) createScheduledTask() error {
-	exePath, err := os.Executable()
-	if err != nil {
-		return fmt.Errorf("获取程序路径失败: %v", err)
-	}
-
-	// 获取核心服务路径
-	exeDir := filepath.Dir(exePath)
-	corePath := filepath.Join(exeDir, "BS2PRO-Core.exe")
-	if _, err := os.Stat(corePath); os.IsNotExist(err) {
-		corePath = exePath
-	}
-	taskCommand := fmt.Sprintf("\"%s\" --autostart", corePath)
-	cmd := exec.Command("schtasks", "/create",
-		"/tn", "BS2PRO-Controller",
-		"/tr", taskCommand,
-		"/sc", "onlogon",
-		"/delay", "0000:15",
-		"/rl", "highest",
-		"/f")
-
-	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("创建任务计划失败: %v, 输出: %s", err, string(output))
-	}
-
-	m.logger.Info("已通过任务计划程序设置开机自启动")
-	return nil
-}
-
-// deleteScheduledTask 删除任务计划程序
-func (m *Manager) deleteScheduledTask() error {
-	cmd := exec.Command("schtasks", "/delete", "/tn", "BS2PRO-Controller", "/f")
-	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		if strings.Contains(string(output), "不存在") || strings.Contains(string(output), "cannot be found") {
-			return nil
-		}
-		return fmt.Errorf("删除任务计划失败: %v, 输出: %s", err, string(output))
-	}
-
-	m.logger.Info("已删除任务计划程序的自启动任务")
-	return nil
-}
-
 // removeRegistryAutoStart 删除注册表自启动项
 func (m *Manager) removeRegistryAutoStart() error {
 	key, err := registry.OpenKey(registry.CURRENT_USER, `SOFTWARE\Microsoft\Windows\CurrentVersion\Run`, registry.SET_VALUE)
@@ -156,14 +124,24 @@ func (m *Manager) GetAutoStartMethod() string {
 	if m.checkRegistryAutoStart() {
 		return "registry"
 	}
+	if m.checkStartupFolderAutoStart() {
+		return "startup_folder"
+	}
+	if m.checkServiceAutoStart() {
+		return "service"
+	}
 	return "none"
 }
 
 // SetAutoStartWithMethod 使用指定方式设置自启动
 func (m *Manager) SetAutoStartWithMethod(enable bool, method string) error {
+	defer m.syncSupervisor(enable)
+
 	if !enable {
 		m.deleteScheduledTask()
 		m.removeRegistryAutoStart()
+		m.removeStartupFolderAutoStart()
+		m.removeServiceAutoStart()
 		return nil
 	}
 
@@ -192,6 +170,30 @@ func (m *Manager) SetAutoStartWithMethod(enable bool, method string) error {
 			return fmt.Errorf("注册表自启动失败，且当前非管理员无法回退任务计划程序: %v", err)
 		}
 
+	case "startup_folder":
+		if err := m.removeRegistryAutoStart(); err != nil {
+			m.logger.Error("清理注册表自启动失败: %v", err)
+		}
+		if err := m.deleteScheduledTask(); err != nil {
+			m.logger.Error("清理任务计划自启动失败: %v", err)
+		}
+		return m.setStartupFolderAutoStart()
+
+	case "service":
+		if err := m.removeRegistryAutoStart(); err != nil {
+			m.logger.Error("清理注册表自启动失败: %v", err)
+		}
+		if err := m.deleteScheduledTask(); err != nil {
+			m.logger.Error("清理任务计划自启动失败: %v", err)
+		}
+		if err := m.removeStartupFolderAutoStart(); err != nil {
+			m.logger.Error("清理启动文件夹自启动失败: %v", err)
+		}
+		if !m.IsRunningAsAdmin() {
+			return fmt.Errorf("安装Windows服务需要管理员权限，请以管理员身份运行程序进行设置")
+		}
+		return m.setServiceAutoStart()
+
 	default:
 		return fmt.Errorf("不支持的自启动方式: %s", method)
 	}
@@ -232,17 +234,14 @@ func (m *Manager) CheckWindowsAutoStart() bool {
 	if m.checkScheduledTask() {
 		return true
 	}
+	if m.checkRegistryAutoStart() {
+		return true
+	}
+	if m.checkStartupFolderAutoStart() {
+		return true
+	}
 
-	return m.checkRegistryAutoStart()
-}
-
-// checkScheduledTask 检查任务计划程序中的自启动任务
-func (m *Manager) checkScheduledTask() bool {
-	cmd := exec.Command("schtasks", "/query", "/tn", "BS2PRO-Controller")
-	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
-
-	err := cmd.Run()
-	return err == nil
+	return m.checkServiceAutoStart()
 }
 
 // checkRegistryAutoStart 检查注册表中的自启动项
@@ -289,62 +288,3 @@ func DetectAutoStartLaunch(args []string) bool {
 	return false
 }
 
-// isLaunchedByTaskScheduler 检查是否由任务计划程序启动
-func isLaunchedByTaskScheduler() bool {
-	// 在Windows上检查父进程
-	cmd := exec.Command("wmic", "process", "where", fmt.Sprintf("ProcessId=%d", os.Getpid()), "get", "ParentProcessId", "/value")
-	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
-
-	output, err := cmd.Output()
-	if err != nil {
-		return false
-	}
-
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if after, ok := strings.CutPrefix(line, "ParentProcessId="); ok {
-			ppidStr := strings.TrimSpace(after)
-			if ppidStr != "" && ppidStr != "0" {
-				ppid, err := parseIntSafe(ppidStr)
-				if err == nil {
-					return checkParentProcessName(ppid)
-				}
-			}
-		}
-	}
-
-	return false
-}
-
-// checkParentProcessName 检查父进程名称
-func checkParentProcessName(ppid int) bool {
-	cmd := exec.Command("wmic", "process", "where", fmt.Sprintf("ProcessId=%d", ppid), "get", "Name", "/value")
-	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
-
-	output, err := cmd.Output()
-	if err != nil {
-		return false
-	}
-
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if after, ok := strings.CutPrefix(line, "Name="); ok {
-			processName := strings.ToLower(strings.TrimSpace(after))
-			// 检查是否为任务计划程序相关进程
-			if processName == "taskeng.exe" || processName == "svchost.exe" || processName == "taskhostw.exe" {
-				return true
-			}
-		}
-	}
-
-	return false
-}
-
-// parseIntSafe 安全解析整数
-func parseIntSafe(s string) (int, error) {
-	var result int
-	_, err := fmt.Sscanf(s, "%d", &result)
-	return result, err
-}
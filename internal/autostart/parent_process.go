@@ -0,0 +1,92 @@
+package autostart
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+var taskSchedulerParentNames = map[string]bool{
+	"taskeng.exe":   true,
+	"svchost.exe":   true,
+	"taskhostw.exe": true,
+	"services.exe":  true,
+}
+
+// ParentProcessName 返回当前进程父进程的可执行文件名，供其他包（如遥测）复用
+func ParentProcessName() (string, error) {
+	ppid, err := parentProcessID(uint32(os.Getpid()))
+	if err != nil {
+		return "", err
+	}
+
+	name, err := processImageName(ppid)
+	if err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
+// isLaunchedByTaskScheduler 通过遍历进程树判断当前进程是否由任务计划程序启动
+func isLaunchedByTaskScheduler() bool {
+	name, err := ParentProcessName()
+	if err != nil {
+		return false
+	}
+
+	return taskSchedulerParentNames[strings.ToLower(name)]
+}
+
+// parentProcessID 通过 CreateToolhelp32Snapshot 遍历进程快照，查找指定 PID 的父进程 PID
+func parentProcessID(pid uint32) (uint32, error) {
+	snapshot, err := syscall.CreateToolhelp32Snapshot(syscall.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return 0, fmt.Errorf("创建进程快照失败: %v", err)
+	}
+	defer syscall.CloseHandle(snapshot)
+
+	var entry syscall.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+
+	if err := syscall.Process32First(snapshot, &entry); err != nil {
+		return 0, fmt.Errorf("读取进程快照首项失败: %v", err)
+	}
+
+	for {
+		if entry.ProcessID == pid {
+			return entry.ParentProcessID, nil
+		}
+		if err := syscall.Process32Next(snapshot, &entry); err != nil {
+			return 0, fmt.Errorf("未在进程快照中找到PID=%d: %v", pid, err)
+		}
+	}
+}
+
+// processImageName 在进程快照中查找指定 PID 对应的可执行文件名
+func processImageName(pid uint32) (string, error) {
+	snapshot, err := syscall.CreateToolhelp32Snapshot(syscall.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return "", fmt.Errorf("创建进程快照失败: %v", err)
+	}
+	defer syscall.CloseHandle(snapshot)
+
+	var entry syscall.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+
+	if err := syscall.Process32First(snapshot, &entry); err != nil {
+		return "", fmt.Errorf("读取进程快照首项失败: %v", err)
+	}
+
+	for {
+		if entry.ProcessID == pid {
+			return syscall.UTF16ToString(entry.ExeFile[:]), nil
+		}
+		if err := syscall.Process32Next(snapshot, &entry); err != nil {
+			return "", fmt.Errorf("未在进程快照中找到PID=%d: %v", pid, err)
+		}
+	}
+}
+
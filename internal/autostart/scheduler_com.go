@@ -0,0 +1,296 @@
+package autostart
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+const (
+	taskFolderRoot = `\`
+	taskName       = "BS2PRO-Controller"
+
+	taskLogonInteractiveToken = 3 // TASK_LOGON_INTERACTIVE_TOKEN
+	taskCreateOrUpdate        = 6 // TASK_CREATE_OR_UPDATE
+	taskRunLevelHighest       = 1 // TASK_RUNLEVEL_HIGHEST
+	taskActionExec            = 0 // TASK_ACTION_EXEC
+	taskTriggerLogon          = 9 // TASK_TRIGGER_LOGON
+
+	taskSchedulerNotFound = "0x80070002" // 任务/文件不存在
+)
+
+// createScheduledTask 通过 Task Scheduler 2.0 COM API 创建开机自启动任务
+func (m *Manager) createScheduledTask() error {
+	corePath, err := m.resolveCorePath()
+	if err != nil {
+		return err
+	}
+
+	if err := ole.CoInitialize(0); err != nil {
+		return fmt.Errorf("初始化COM失败: %v", err)
+	}
+	defer ole.CoUninitialize()
+
+	service, release, err := connectTaskService()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	folder, err := oleutil.CallMethod(service, "GetFolder", taskFolderRoot)
+	if err != nil {
+		return fmt.Errorf("获取任务计划根目录失败: %v", err)
+	}
+	taskFolder := folder.ToIDispatch()
+	defer taskFolder.Release()
+
+	definition, err := oleutil.CallMethod(service, "NewTask", 0)
+	if err != nil {
+		return fmt.Errorf("创建任务定义失败: %v", err)
+	}
+	taskDef := definition.ToIDispatch()
+	defer taskDef.Release()
+
+	if err := configureRegistrationInfo(taskDef); err != nil {
+		return err
+	}
+	if err := configurePrincipal(taskDef); err != nil {
+		return err
+	}
+	if err := configureSettings(taskDef); err != nil {
+		return err
+	}
+	if err := configureLogonTrigger(taskDef); err != nil {
+		return err
+	}
+	if err := configureExecAction(taskDef, corePath); err != nil {
+		return err
+	}
+
+	_, err = oleutil.CallMethod(taskFolder, "RegisterTaskDefinition",
+		taskName, taskDef, taskCreateOrUpdate, nil, nil, taskLogonInteractiveToken)
+	if err != nil {
+		return fmt.Errorf("注册任务计划失败: %v", err)
+	}
+
+	m.logger.Info("已通过任务计划程序设置开机自启动")
+	return nil
+}
+
+// deleteScheduledTask 删除任务计划程序中的自启动任务
+func (m *Manager) deleteScheduledTask() error {
+	if err := ole.CoInitialize(0); err != nil {
+		return fmt.Errorf("初始化COM失败: %v", err)
+	}
+	defer ole.CoUninitialize()
+
+	service, release, err := connectTaskService()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	folder, err := oleutil.CallMethod(service, "GetFolder", taskFolderRoot)
+	if err != nil {
+		return fmt.Errorf("获取任务计划根目录失败: %v", err)
+	}
+	taskFolder := folder.ToIDispatch()
+	defer taskFolder.Release()
+
+	_, err = oleutil.CallMethod(taskFolder, "DeleteTask", taskName, 0)
+	if err != nil {
+		if oleErrorCode(err) == taskSchedulerNotFound {
+			return nil
+		}
+		return fmt.Errorf("删除任务计划失败: %v", err)
+	}
+
+	m.logger.Info("已删除任务计划程序的自启动任务")
+	return nil
+}
+
+// checkScheduledTask 检查任务计划程序中是否存在自启动任务
+func (m *Manager) checkScheduledTask() bool {
+	if err := ole.CoInitialize(0); err != nil {
+		return false
+	}
+	defer ole.CoUninitialize()
+
+	service, release, err := connectTaskService()
+	if err != nil {
+		return false
+	}
+	defer release()
+
+	folder, err := oleutil.CallMethod(service, "GetFolder", taskFolderRoot)
+	if err != nil {
+		return false
+	}
+	taskFolder := folder.ToIDispatch()
+	defer taskFolder.Release()
+
+	task, err := oleutil.CallMethod(taskFolder, "GetTask", taskName)
+	if err != nil {
+		if oleErrorCode(err) == taskSchedulerNotFound {
+			return false
+		}
+		m.logger.Debug("查询任务计划失败: %v", err)
+		return false
+	}
+	task.ToIDispatch().Release()
+
+	return true
+}
+
+// connectTaskService 创建并连接 ITaskService，返回释放函数
+func connectTaskService() (*ole.IDispatch, func(), error) {
+	unknown, err := oleutil.CreateObject("Schedule.Service")
+	if err != nil {
+		return nil, nil, fmt.Errorf("创建任务计划服务失败: %v", err)
+	}
+
+	service, err := unknown.QueryInterface(ole.IID_IDispatch)
+	if err != nil {
+		unknown.Release()
+		return nil, nil, fmt.Errorf("获取ITaskService接口失败: %v", err)
+	}
+
+	if _, err := oleutil.CallMethod(service, "Connect"); err != nil {
+		service.Release()
+		unknown.Release()
+		return nil, nil, fmt.Errorf("连接任务计划服务失败: %v", err)
+	}
+
+	release := func() {
+		service.Release()
+		unknown.Release()
+	}
+	return service, release, nil
+}
+
+func configureRegistrationInfo(taskDef *ole.IDispatch) error {
+	info, err := oleutil.GetProperty(taskDef, "RegistrationInfo")
+	if err != nil {
+		return fmt.Errorf("获取RegistrationInfo失败: %v", err)
+	}
+	registrationInfo := info.ToIDispatch()
+	defer registrationInfo.Release()
+
+	if _, err := oleutil.PutProperty(registrationInfo, "Author", taskName); err != nil {
+		return fmt.Errorf("设置任务作者失败: %v", err)
+	}
+	if _, err := oleutil.PutProperty(registrationInfo, "Description", "BS2PRO-Controller 开机自启动"); err != nil {
+		return fmt.Errorf("设置任务描述失败: %v", err)
+	}
+	return nil
+}
+
+func configurePrincipal(taskDef *ole.IDispatch) error {
+	principal, err := oleutil.GetProperty(taskDef, "Principal")
+	if err != nil {
+		return fmt.Errorf("获取Principal失败: %v", err)
+	}
+	principalDispatch := principal.ToIDispatch()
+	defer principalDispatch.Release()
+
+	if _, err := oleutil.PutProperty(principalDispatch, "LogonType", taskLogonInteractiveToken); err != nil {
+		return fmt.Errorf("设置登录类型失败: %v", err)
+	}
+	if _, err := oleutil.PutProperty(principalDispatch, "RunLevel", taskRunLevelHighest); err != nil {
+		return fmt.Errorf("设置运行级别失败: %v", err)
+	}
+	return nil
+}
+
+func configureSettings(taskDef *ole.IDispatch) error {
+	settings, err := oleutil.GetProperty(taskDef, "Settings")
+	if err != nil {
+		return fmt.Errorf("获取Settings失败: %v", err)
+	}
+	settingsDispatch := settings.ToIDispatch()
+	defer settingsDispatch.Release()
+
+	props := map[string]any{
+		"DisallowStartIfOnBatteries": false,
+		"StopIfGoingOnBatteries":     false,
+		"AllowHardTerminate":         true,
+		"MultipleInstances":          2, // TASK_INSTANCES_IGNORE_NEW
+	}
+	for name, value := range props {
+		if _, err := oleutil.PutProperty(settingsDispatch, name, value); err != nil {
+			return fmt.Errorf("设置任务属性%s失败: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func configureLogonTrigger(taskDef *ole.IDispatch) error {
+	triggers, err := oleutil.GetProperty(taskDef, "Triggers")
+	if err != nil {
+		return fmt.Errorf("获取Triggers失败: %v", err)
+	}
+	triggerCollection := triggers.ToIDispatch()
+	defer triggerCollection.Release()
+
+	trigger, err := oleutil.CallMethod(triggerCollection, "Create", taskTriggerLogon)
+	if err != nil {
+		return fmt.Errorf("创建登录触发器失败: %v", err)
+	}
+	triggerDispatch := trigger.ToIDispatch()
+	defer triggerDispatch.Release()
+
+	if _, err := oleutil.PutProperty(triggerDispatch, "Delay", "PT15S"); err != nil {
+		return fmt.Errorf("设置触发延迟失败: %v", err)
+	}
+	return nil
+}
+
+func configureExecAction(taskDef *ole.IDispatch, corePath string) error {
+	actions, err := oleutil.GetProperty(taskDef, "Actions")
+	if err != nil {
+		return fmt.Errorf("获取Actions失败: %v", err)
+	}
+	actionCollection := actions.ToIDispatch()
+	defer actionCollection.Release()
+
+	action, err := oleutil.CallMethod(actionCollection, "Create", taskActionExec)
+	if err != nil {
+		return fmt.Errorf("创建执行操作失败: %v", err)
+	}
+	actionDispatch := action.ToIDispatch()
+	defer actionDispatch.Release()
+
+	if _, err := oleutil.PutProperty(actionDispatch, "Path", corePath); err != nil {
+		return fmt.Errorf("设置执行路径失败: %v", err)
+	}
+	if _, err := oleutil.PutProperty(actionDispatch, "Arguments", "--autostart"); err != nil {
+		return fmt.Errorf("设置执行参数失败: %v", err)
+	}
+	return nil
+}
+
+// resolveCorePath 解析核心服务可执行文件路径，不存在时回退到当前程序路径
+func (m *Manager) resolveCorePath() (string, error) {
+	exePath, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("获取程序路径失败: %v", err)
+	}
+
+	corePath := filepath.Join(filepath.Dir(exePath), "BS2PRO-Core.exe")
+	if _, err := os.Stat(corePath); os.IsNotExist(err) {
+		return exePath, nil
+	}
+	return corePath, nil
+}
+
+// oleErrorCode 从 go-ole 错误中提取形如 "0x80070002" 的 HRESULT 字符串
+func oleErrorCode(err error) string {
+	oleErr, ok := err.(*ole.OleError)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("0x%08X", uint32(oleErr.Code()))
+}
@@ -0,0 +1,99 @@
+package autostart
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+const (
+	serviceName        = "BS2PRO-Controller"
+	serviceDisplayName = "BS2PRO Controller Service"
+	serviceDescription = "为 BS2PRO 设备提供开机即可用的后台控制与智能调速服务"
+)
+
+// setServiceAutoStart 将核心服务注册为延迟自动启动的 Windows 服务
+func (m *Manager) setServiceAutoStart() error {
+	corePath, err := m.resolveCorePath()
+	if err != nil {
+		return err
+	}
+
+	manager, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("连接服务控制管理器失败: %v", err)
+	}
+	defer manager.Disconnect()
+
+	if existing, err := manager.OpenService(serviceName); err == nil {
+		existing.Close()
+		if err := m.removeServiceAutoStart(); err != nil {
+			return fmt.Errorf("移除已存在的服务失败: %v", err)
+		}
+	}
+
+	service, err := manager.CreateService(serviceName, corePath, mgr.Config{
+		StartType:        mgr.StartAutomatic,
+		DisplayName:      serviceDisplayName,
+		Description:      serviceDescription,
+		DelayedAutoStart: true,
+	}, "--service", "--autostart")
+	if err != nil {
+		return fmt.Errorf("创建Windows服务失败: %v", err)
+	}
+	defer service.Close()
+
+	recoveryActions := []mgr.RecoveryAction{
+		{Type: mgr.ServiceRestart, Delay: 5 * time.Second},
+		{Type: mgr.ServiceRestart, Delay: 30 * time.Second},
+		{Type: mgr.ServiceRestart, Delay: 60 * time.Second},
+	}
+	if err := service.SetRecoveryActions(recoveryActions, uint32((24 * time.Hour).Seconds())); err != nil {
+		m.logger.Error("设置服务故障恢复策略失败: %v", err)
+	}
+
+	m.logger.Info("已将核心服务注册为Windows服务并设置开机自启动")
+	return nil
+}
+
+// removeServiceAutoStart 停止并删除核心服务对应的Windows服务
+func (m *Manager) removeServiceAutoStart() error {
+	manager, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("连接服务控制管理器失败: %v", err)
+	}
+	defer manager.Disconnect()
+
+	service, err := manager.OpenService(serviceName)
+	if err != nil {
+		return nil
+	}
+	defer service.Close()
+
+	service.Control(svc.Stop)
+	if err := service.Delete(); err != nil {
+		return fmt.Errorf("删除Windows服务失败: %v", err)
+	}
+
+	m.logger.Info("已删除Windows服务自启动")
+	return nil
+}
+
+// checkServiceAutoStart 检查核心服务对应的Windows服务是否存在
+func (m *Manager) checkServiceAutoStart() bool {
+	manager, err := mgr.Connect()
+	if err != nil {
+		return false
+	}
+	defer manager.Disconnect()
+
+	service, err := manager.OpenService(serviceName)
+	if err != nil {
+		return false
+	}
+	service.Close()
+
+	return true
+}
@@ -0,0 +1,113 @@
+package autostart
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+	"golang.org/x/sys/windows"
+)
+
+const shortcutName = "BS2PRO-Controller.lnk"
+
+// setStartupFolderAutoStart 在当前用户的启动文件夹中创建指向核心服务的快捷方式
+func (m *Manager) setStartupFolderAutoStart() error {
+	corePath, err := m.resolveCorePath()
+	if err != nil {
+		return err
+	}
+
+	shortcutPath, err := startupShortcutPath()
+	if err != nil {
+		return err
+	}
+
+	if err := ole.CoInitialize(0); err != nil {
+		return fmt.Errorf("初始化COM失败: %v", err)
+	}
+	defer ole.CoUninitialize()
+
+	unknown, err := oleutil.CreateObject("WScript.Shell")
+	if err != nil {
+		return fmt.Errorf("创建WScript.Shell对象失败: %v", err)
+	}
+	defer unknown.Release()
+
+	shell, err := unknown.QueryInterface(ole.IID_IDispatch)
+	if err != nil {
+		return fmt.Errorf("获取IDispatch接口失败: %v", err)
+	}
+	defer shell.Release()
+
+	shortcut, err := oleutil.CallMethod(shell, "CreateShortcut", shortcutPath)
+	if err != nil {
+		return fmt.Errorf("创建快捷方式失败: %v", err)
+	}
+	shortcutDispatch := shortcut.ToIDispatch()
+	defer shortcutDispatch.Release()
+
+	if _, err := oleutil.PutProperty(shortcutDispatch, "TargetPath", corePath); err != nil {
+		return fmt.Errorf("设置快捷方式目标失败: %v", err)
+	}
+	if _, err := oleutil.PutProperty(shortcutDispatch, "Arguments", "--autostart"); err != nil {
+		return fmt.Errorf("设置快捷方式参数失败: %v", err)
+	}
+	if _, err := oleutil.PutProperty(shortcutDispatch, "WorkingDirectory", filepath.Dir(corePath)); err != nil {
+		return fmt.Errorf("设置快捷方式工作目录失败: %v", err)
+	}
+	if _, err := oleutil.PutProperty(shortcutDispatch, "WindowStyle", 7); err != nil { // 7 = 最小化启动
+		return fmt.Errorf("设置快捷方式窗口样式失败: %v", err)
+	}
+	if _, err := oleutil.PutProperty(shortcutDispatch, "IconLocation", corePath+",0"); err != nil {
+		return fmt.Errorf("设置快捷方式图标失败: %v", err)
+	}
+
+	if _, err := oleutil.CallMethod(shortcutDispatch, "Save"); err != nil {
+		return fmt.Errorf("保存快捷方式失败: %v", err)
+	}
+
+	m.logger.Info("已通过启动文件夹设置开机自启动")
+	return nil
+}
+
+// removeStartupFolderAutoStart 删除启动文件夹中的自启动快捷方式
+func (m *Manager) removeStartupFolderAutoStart() error {
+	shortcutPath, err := startupShortcutPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(shortcutPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("删除启动文件夹快捷方式失败: %v", err)
+	}
+
+	m.logger.Info("已删除启动文件夹的自启动快捷方式")
+	return nil
+}
+
+// checkStartupFolderAutoStart 检查启动文件夹中是否存在自启动快捷方式
+func (m *Manager) checkStartupFolderAutoStart() bool {
+	shortcutPath, err := startupShortcutPath()
+	if err != nil {
+		return false
+	}
+
+	_, err = os.Stat(shortcutPath)
+	return err == nil
+}
+
+// startupShortcutPath 解析当前用户启动文件夹下的快捷方式路径
+func startupShortcutPath() (string, error) {
+	startupDir, err := windows.KnownFolderPath(windows.FOLDERID_Startup, 0)
+	if err != nil {
+		appData, appDataErr := os.UserConfigDir()
+		if appDataErr != nil {
+			return "", fmt.Errorf("获取启动文件夹路径失败: %v", err)
+		}
+		startupDir = filepath.Join(appData, "Microsoft", "Windows", "Start Menu", "Programs", "Startup")
+	}
+
+	return filepath.Join(startupDir, shortcutName), nil
+}
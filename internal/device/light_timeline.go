@@ -0,0 +1,129 @@
+package device
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// SetLightTimeline 按自定义关键帧时间轴驱动灯带，供第三方特效编辑器使用
+func (m *Manager) SetLightTimeline(timeline types.LightTimeline) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.isConnected || m.device == nil {
+		return fmt.Errorf("设备未连接")
+	}
+	if len(timeline.Keyframes) < 2 {
+		return fmt.Errorf("时间轴至少需要 2 个关键帧")
+	}
+
+	speed := parseLightSpeed(timeline.Speed)
+	baseColor := timeline.Keyframes[0].Colors[0]
+	brightness := clampLightBrightness(int(timeline.Keyframes[0].Brightness))
+	f0 := makeLightF0(0x00, speed, brightness, baseColor)
+
+	var frames [30][10]byte
+	for i := range 30 {
+		t := float64(i) / 29.0
+		left, right := bracketingKeyframes(timeline.Keyframes, t)
+		frames[i] = sampleKeyframe(left, right, t)
+	}
+
+	return m.applyLightFramesLocked(f0, frames)
+}
+
+// bracketingKeyframes 返回包含时间点 t 的一对相邻关键帧
+func bracketingKeyframes(keyframes []types.LightKeyframe, t float64) (types.LightKeyframe, types.LightKeyframe) {
+	for i := 0; i < len(keyframes)-1; i++ {
+		if t >= keyframes[i].T && t <= keyframes[i+1].T {
+			return keyframes[i], keyframes[i+1]
+		}
+	}
+	if t < keyframes[0].T {
+		return keyframes[0], keyframes[0]
+	}
+	last := keyframes[len(keyframes)-1]
+	return last, last
+}
+
+// sampleKeyframe 在 left/right 关键帧之间按 left 段的缓动函数插值出一帧
+func sampleKeyframe(left, right types.LightKeyframe, t float64) [10]byte {
+	span := right.T - left.T
+	progress := 0.0
+	if span > 0 {
+		progress = (t - left.T) / span
+	}
+	eased := applyEasing(left.Easing, progress)
+
+	brightness := lerp(float64(left.Brightness), float64(right.Brightness), eased) / 100.0
+
+	// 每个灯珠的 R/G/B 三个通道落在 3 个连续字节(与协议的帧布局一致，参见 rgb.go)；
+	// 一个 10 字节帧最多容纳 3 颗灯珠的完整颜色，故只取前 3 颗
+	var frame [10]byte
+	for i := range 3 {
+		frame[i*3] = byte(lerp(float64(left.Colors[i].R), float64(right.Colors[i].R), eased) * brightness)
+		frame[i*3+1] = byte(lerp(float64(left.Colors[i].G), float64(right.Colors[i].G), eased) * brightness)
+		frame[i*3+2] = byte(lerp(float64(left.Colors[i].B), float64(right.Colors[i].B), eased) * brightness)
+	}
+
+	return frame
+}
+
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+// applyEasing 对 progress(0~1) 按命名缓动函数整形，ease_in_out 使用三次贝塞尔 (0.42,0,0.58,1) 近似
+func applyEasing(name string, progress float64) float64 {
+	progress = clampFloat01(progress)
+	switch name {
+	case "ease_in":
+		return progress * progress * progress
+	case "ease_out":
+		inv := 1 - progress
+		return 1 - inv*inv*inv
+	case "ease_in_out":
+		return cubicBezierEaseInOut(progress)
+	default:
+		return progress
+	}
+}
+
+// cubicBezierEaseInOut 近似三次贝塞尔 (0.42,0,0.58,1) 的 y(x)，通过牛顿迭代求解贝塞尔参数 u
+func cubicBezierEaseInOut(x float64) float64 {
+	const x1, y1, x2, y2 = 0.42, 0.0, 0.58, 1.0
+
+	u := x
+	for range 4 {
+		bx := bezierComponent(x1, x2, u) - x
+		d := bezierDerivative(x1, x2, u)
+		if math.Abs(d) < 1e-6 {
+			break
+		}
+		u -= bx / d
+		u = clampFloat01(u)
+	}
+	return bezierComponent(y1, y2, u)
+}
+
+func bezierComponent(p1, p2, u float64) float64 {
+	v := 1 - u
+	return 3*v*v*u*p1 + 3*v*u*u*p2 + u*u*u
+}
+
+func bezierDerivative(p1, p2, u float64) float64 {
+	v := 1 - u
+	return 3*v*v*p1 + 6*v*u*(p2-p1) + 3*u*u*(1-p2)
+}
+
+func clampFloat01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
@@ -0,0 +1,93 @@
+// Package echonet 将 BS2PRO 控制器以 ECHONET Lite 节点的形式暴露在局域网内，
+// 供 Home Assistant/HomeBridge 等智能家居控制器读取温度并驱动风扇行为。
+package echonet
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	ehd1 = 0x10
+	ehd2 = 0x81
+
+	// ESV 服务代码
+	esvGet        = 0x62
+	esvGetRes     = 0x72
+	esvSetC       = 0x61
+	esvSetRes     = 0x71
+	esvSetGetFail = 0x51
+	esvInf        = 0x73
+
+	// 节点/超类公共 EOJ
+	classGroupProfile = 0x0E
+	classCodeNodeProf = 0xF0
+	instanceCode      = 0x01
+
+	multicastAddr = "224.0.23.0:3610"
+	listenPort    = 3610
+)
+
+// eoj 对象标识(类组码+类码+实例码)
+type eoj [3]byte
+
+// property 单个 EPC/PDC/EDT 属性
+type property struct {
+	epc byte
+	edt []byte
+}
+
+// frame 一个完整的 ECHONET Lite 帧
+type frame struct {
+	tid   uint16
+	seoj  eoj
+	deoj  eoj
+	esv   byte
+	props []property
+}
+
+// parseFrame 解析收到的 ECHONET Lite 报文，格式不合法时返回错误
+func parseFrame(data []byte) (frame, error) {
+	if len(data) < 12 {
+		return frame{}, fmt.Errorf("报文过短: %d 字节", len(data))
+	}
+	if data[0] != ehd1 || data[1] != ehd2 {
+		return frame{}, fmt.Errorf("非ECHONET Lite报文: EHD=%02x%02x", data[0], data[1])
+	}
+
+	f := frame{
+		tid:  binary.BigEndian.Uint16(data[2:4]),
+		seoj: eoj{data[4], data[5], data[6]},
+		deoj: eoj{data[7], data[8], data[9]},
+		esv:  data[10],
+	}
+
+	opc := int(data[11])
+	offset := 12
+	for range opc {
+		if offset+2 > len(data) {
+			return frame{}, fmt.Errorf("属性字段越界")
+		}
+		epc := data[offset]
+		pdc := int(data[offset+1])
+		offset += 2
+		if offset+pdc > len(data) {
+			return frame{}, fmt.Errorf("属性数据越界")
+		}
+		edt := append([]byte(nil), data[offset:offset+pdc]...)
+		offset += pdc
+		f.props = append(f.props, property{epc: epc, edt: edt})
+	}
+
+	return f, nil
+}
+
+// encode 将帧序列化为 ECHONET Lite 报文字节
+func (f frame) encode() []byte {
+	buf := []byte{ehd1, ehd2, byte(f.tid >> 8), byte(f.tid), f.seoj[0], f.seoj[1], f.seoj[2], f.deoj[0], f.deoj[1], f.deoj[2], f.esv, byte(len(f.props))}
+	for _, p := range f.props {
+		buf = append(buf, p.epc, byte(len(p.edt)))
+		buf = append(buf, p.edt...)
+	}
+	return buf
+}
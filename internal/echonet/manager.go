@@ -0,0 +1,326 @@
+package echonet
+
+import (
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// StateProvider 向 ECHONET 节点提供当前需要对外暴露的设备状态
+type StateProvider interface {
+	FanData() types.FanData
+	TemperatureData() types.TemperatureData
+	AutoControl() bool
+}
+
+// CommandHandler 接收来自 ECHONET SetC 请求的控制命令
+type CommandHandler interface {
+	SetAutoControl(auto bool) error
+}
+
+// Manager ECHONET Lite 节点，负责收发 UDP 报文并维护对外属性
+type Manager struct {
+	logger  types.Logger
+	state   StateProvider
+	handler CommandHandler
+
+	mu      sync.Mutex
+	conn    *net.UDPConn
+	stopCh  chan struct{}
+	running bool
+
+	allowedAddrs map[string]bool
+
+	lastRPM     uint16
+	lastMaxTemp int
+}
+
+// NewManager 创建一个 ECHONET Lite 节点管理器
+func NewManager(logger types.Logger, state StateProvider, handler CommandHandler) *Manager {
+	return &Manager{
+		logger:  logger,
+		state:   state,
+		handler: handler,
+	}
+}
+
+// Start 启动 UDP 监听，cfg.Enabled 为 false 时直接返回不做任何事
+func (m *Manager) Start(cfg types.EchonetConfig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !cfg.Enabled {
+		return nil
+	}
+	if m.running {
+		return nil
+	}
+
+	addr := &net.UDPAddr{Port: listenPort}
+	conn, err := net.ListenUDP("udp4", addr)
+	if err != nil {
+		return fmt.Errorf("监听ECHONET Lite端口失败: %v", err)
+	}
+
+	m.conn = conn
+	m.stopCh = make(chan struct{})
+	m.running = true
+	m.allowedAddrs = toAddrSet(cfg.AllowedAddresses)
+
+	go m.readLoop(conn, m.stopCh)
+
+	m.logger.Info("ECHONET Lite 节点已启动，监听端口 %d", listenPort)
+	return nil
+}
+
+// Stop 停止 UDP 监听
+func (m *Manager) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.running {
+		return
+	}
+	close(m.stopCh)
+	m.conn.Close()
+	m.running = false
+}
+
+func toAddrSet(addrs []string) map[string]bool {
+	if len(addrs) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(addrs))
+	for _, a := range addrs {
+		set[a] = true
+	}
+	return set
+}
+
+func (m *Manager) isAllowed(addr *net.UDPAddr) bool {
+	m.mu.Lock()
+	allowed := m.allowedAddrs
+	m.mu.Unlock()
+
+	if len(allowed) == 0 {
+		return true
+	}
+	return allowed[addr.IP.String()]
+}
+
+func (m *Manager) readLoop(conn *net.UDPConn, stopCh chan struct{}) {
+	buf := make([]byte, 1500)
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+		}
+
+		n, remoteAddr, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			select {
+			case <-stopCh:
+				return
+			default:
+				m.logger.Debug("ECHONET Lite 读取报文失败: %v", err)
+				continue
+			}
+		}
+
+		if !m.isAllowed(remoteAddr) {
+			continue
+		}
+
+		f, err := parseFrame(buf[:n])
+		if err != nil {
+			m.logger.Debug("ECHONET Lite 解析报文失败: %v", err)
+			continue
+		}
+
+		if resp, ok := m.handleFrame(f); ok {
+			if _, err := conn.WriteToUDP(resp.encode(), remoteAddr); err != nil {
+				m.logger.Debug("ECHONET Lite 发送响应失败: %v", err)
+			}
+		}
+	}
+}
+
+// handleFrame 根据 ESV 分发请求，返回需要回复的响应帧
+func (m *Manager) handleFrame(req frame) (frame, bool) {
+	switch req.esv {
+	case esvGet:
+		return m.handleGet(req), true
+	case esvSetC:
+		return m.handleSetC(req), true
+	default:
+		return frame{}, false
+	}
+}
+
+func (m *Manager) handleGet(req frame) frame {
+	resp := frame{tid: req.tid, seoj: req.deoj, deoj: req.seoj, esv: esvGetRes}
+	for _, p := range req.props {
+		edt, ok := m.readProperty(req.deoj, p.epc)
+		if !ok {
+			resp.esv = esvSetGetFail
+			resp.props = append(resp.props, property{epc: p.epc})
+			continue
+		}
+		resp.props = append(resp.props, property{epc: p.epc, edt: edt})
+	}
+	return resp
+}
+
+func (m *Manager) handleSetC(req frame) frame {
+	resp := frame{tid: req.tid, seoj: req.deoj, deoj: req.seoj, esv: esvSetRes}
+	for _, p := range req.props {
+		if !m.writeProperty(req.deoj, p.epc, p.edt) {
+			resp.esv = esvSetGetFail
+		}
+		resp.props = append(resp.props, property{epc: p.epc})
+	}
+	return resp
+}
+
+// readProperty 读取指定对象上某个 EPC 的当前值
+func (m *Manager) readProperty(target eoj, epc byte) ([]byte, bool) {
+	switch epc {
+	case epcOperatingStatus:
+		return []byte{statusOn}, true
+	case epcSpecVersion:
+		return []byte{0x00, 0x00, 0x4D, 0x01}, true
+	case epcFaultStatus:
+		return []byte{0x42}, true // 无故障
+	case epcSetPropertyMap, epcGetPropertyMap, epcAnnoPropertyMap:
+		return propertyMapEDT(profilePropertyMap), true
+	}
+
+	switch target {
+	case classFanController:
+		return m.readFanProperty(epc)
+	case classTemperatureSensor:
+		return m.readTemperatureProperty(epc)
+	}
+	return nil, false
+}
+
+func (m *Manager) readFanProperty(epc byte) ([]byte, bool) {
+	data := m.state.FanData()
+	switch epc {
+	case epcFanSpeedLevel:
+		return []byte{fanSpeedLevelFromRPM(data.CurrentRPM)}, true
+	case epcTargetRPM:
+		return []byte{byte(data.TargetRPM >> 8), byte(data.TargetRPM)}, true
+	case epcAutoMode:
+		if m.state.AutoControl() {
+			return []byte{autoModeAuto}, true
+		}
+		return []byte{autoModeManual}, true
+	}
+	return nil, false
+}
+
+func (m *Manager) readTemperatureProperty(epc byte) ([]byte, bool) {
+	if epc != epcMeasuredTemp {
+		return nil, false
+	}
+	temp := m.state.TemperatureData().MaxTemp * 10
+	return []byte{byte(temp >> 8), byte(temp)}, true
+}
+
+// writeProperty 处理 SetC 请求对某个 EPC 的写入
+func (m *Manager) writeProperty(target eoj, epc byte, edt []byte) bool {
+	if target != classFanController || epc != epcAutoMode || len(edt) != 1 {
+		return false
+	}
+	if m.handler == nil {
+		return false
+	}
+
+	switch edt[0] {
+	case autoModeAuto:
+		return m.handler.SetAutoControl(true) == nil
+	case autoModeManual:
+		return m.handler.SetAutoControl(false) == nil
+	default:
+		return false
+	}
+}
+
+// fanSpeedLevelFromRPM 将当前转速粗略映射到 1-8 的风量级别，供 ECHONET 空气清洁器属性使用
+func fanSpeedLevelFromRPM(rpm uint16) byte {
+	level := int(rpm)/500 + 1
+	if level < 1 {
+		level = 1
+	}
+	if level > 8 {
+		level = 8
+	}
+	return byte(level)
+}
+
+func propertyMapEDT(epcs []byte) []byte {
+	edt := make([]byte, 0, len(epcs)+1)
+	edt = append(edt, byte(len(epcs)))
+	edt = append(edt, epcs...)
+	return edt
+}
+
+// NotifyIfChanged 在转速或最高温度变化超过阈值时，向 224.0.23.0 多播 INF 通知
+func (m *Manager) NotifyIfChanged(rpmThreshold uint16, tempThreshold int) {
+	m.mu.Lock()
+	running := m.running
+	conn := m.conn
+	m.mu.Unlock()
+	if !running {
+		return
+	}
+
+	data := m.state.FanData()
+	temp := m.state.TemperatureData()
+
+	rpmDelta := absInt(int(data.CurrentRPM) - int(m.lastRPM))
+	tempDelta := absInt(temp.MaxTemp - m.lastMaxTemp)
+	if rpmDelta < int(rpmThreshold) && tempDelta < tempThreshold {
+		return
+	}
+	m.lastRPM = data.CurrentRPM
+	m.lastMaxTemp = temp.MaxTemp
+
+	m.sendInf(conn, classFanController, epcFanSpeedLevel, []byte{fanSpeedLevelFromRPM(data.CurrentRPM)})
+
+	tempEDT := temp.MaxTemp * 10
+	m.sendInf(conn, classTemperatureSensor, epcMeasuredTemp, []byte{byte(tempEDT >> 8), byte(tempEDT)})
+}
+
+func (m *Manager) sendInf(conn *net.UDPConn, source eoj, epc byte, edt []byte) {
+	if conn == nil {
+		return
+	}
+
+	infFrame := frame{
+		seoj:  source,
+		deoj:  classNodeProfile,
+		esv:   esvInf,
+		props: []property{{epc: epc, edt: edt}},
+	}
+
+	dst, err := net.ResolveUDPAddr("udp4", multicastAddr)
+	if err != nil {
+		m.logger.Debug("ECHONET Lite 解析多播地址失败: %v", err)
+		return
+	}
+	if _, err := conn.WriteToUDP(infFrame.encode(), dst); err != nil {
+		m.logger.Debug("ECHONET Lite 发送INF通知失败: %v", err)
+	}
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,39 @@
+package echonet
+
+// EPC 设备通用/专有属性代码
+const (
+	epcOperatingStatus = 0x80 // 运行状态(ON/OFF)
+	epcSpecVersion     = 0x82
+	epcFaultStatus     = 0x88
+	epcSetPropertyMap  = 0x9E
+	epcGetPropertyMap  = 0x9F
+	epcAnnoPropertyMap = 0x9D
+
+	// 风扇(空气清洁器类, 0x0135)专有 EPC
+	epcFanSpeedLevel = 0xF1 // 风量级别(1-8)
+	epcTargetRPM     = 0xF2 // 目标转速(厂商扩展)
+	epcAutoMode      = 0xF3 // 自动/手动模式(0x41=自动 0x42=手动)
+
+	// 温度传感器(0x0011)专有 EPC
+	epcMeasuredTemp = 0xE0 // 测量温度，单位0.1℃，有符号
+)
+
+const (
+	statusOn  = 0x30
+	statusOff = 0x31
+
+	autoModeAuto   = 0x41
+	autoModeManual = 0x42
+)
+
+// classFanController 通用空调/风扇类对象(空气清洁器类组 0x01, 类码 0x35)
+var classFanController = eoj{0x01, 0x35, instanceCode}
+
+// classTemperatureSensor 温度传感器类对象(类组 0x00, 类码 0x11)
+var classTemperatureSensor = eoj{0x00, 0x11, instanceCode}
+
+// classNodeProfile 节点自身的节点配置文件对象
+var classNodeProfile = eoj{classGroupProfile, classCodeNodeProf, instanceCode}
+
+// profilePropertyMap 三类对象共用的 Profile/状态属性可读集合(0x9D/0x9E/0x9F)
+var profilePropertyMap = []byte{epcOperatingStatus, epcSpecVersion, epcFaultStatus, epcSetPropertyMap, epcGetPropertyMap, epcAnnoPropertyMap}
@@ -0,0 +1,78 @@
+// Package modbus 以 Modbus TCP 从站的形式暴露风扇控制与遥测数据，
+// 供 PLC、Node-RED 等工业侧控制器在不依赖 ECHONET 协议栈的情况下轮询与下发指令。
+package modbus
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Modbus 功能码
+const (
+	fcReadCoils            = 0x01
+	fcReadHoldingRegisters = 0x03
+	fcReadInputRegisters   = 0x04
+	fcWriteSingleCoil      = 0x05
+	fcWriteSingleRegister  = 0x06
+	fcWriteMultipleCoils   = 0x0F
+	fcWriteMultipleRegs    = 0x10
+)
+
+// Modbus 异常码
+const (
+	excIllegalFunction    = 0x01
+	excIllegalDataAddress = 0x02
+	excIllegalDataValue   = 0x03
+)
+
+const mbapHeaderLen = 7
+
+// adu 一个完整的 Modbus TCP 应用数据单元(MBAP头 + PDU)
+type adu struct {
+	transactionID uint16
+	unitID        byte
+	functionCode  byte
+	data          []byte
+}
+
+// parseADU 从一次 TCP 读取中解析出请求，长度不合法时返回错误
+func parseADU(buf []byte) (adu, error) {
+	if len(buf) < mbapHeaderLen+1 {
+		return adu{}, fmt.Errorf("报文过短: %d 字节", len(buf))
+	}
+
+	protocolID := binary.BigEndian.Uint16(buf[2:4])
+	if protocolID != 0 {
+		return adu{}, fmt.Errorf("非法协议标识: %d", protocolID)
+	}
+
+	length := binary.BigEndian.Uint16(buf[4:6])
+	if int(length) != len(buf)-6 {
+		return adu{}, fmt.Errorf("长度字段不匹配: 声明%d实际%d", length, len(buf)-6)
+	}
+
+	return adu{
+		transactionID: binary.BigEndian.Uint16(buf[0:2]),
+		unitID:        buf[6],
+		functionCode:  buf[7],
+		data:          buf[8:],
+	}, nil
+}
+
+// encodeResponse 将响应 PDU(functionCode + data)封装为完整的 MBAP 报文
+func encodeResponse(req adu, functionCode byte, data []byte) []byte {
+	pduLen := 1 + len(data)
+	buf := make([]byte, mbapHeaderLen+pduLen)
+	binary.BigEndian.PutUint16(buf[0:2], req.transactionID)
+	binary.BigEndian.PutUint16(buf[2:4], 0)
+	binary.BigEndian.PutUint16(buf[4:6], uint16(1+pduLen))
+	buf[6] = req.unitID
+	buf[7] = functionCode
+	copy(buf[8:], data)
+	return buf
+}
+
+// encodeException 封装一个异常响应，功能码的最高位被置位
+func encodeException(req adu, code byte) []byte {
+	return encodeResponse(req, req.functionCode|0x80, []byte{code})
+}
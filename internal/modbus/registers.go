@@ -0,0 +1,330 @@
+package modbus
+
+import (
+	"fmt"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// 保留寄存器/线圈的容量，越界地址一律返回 0x02 非法数据地址
+const (
+	holdingRegisterCount = 16
+	inputRegisterCount   = 16
+	coilCount            = 8
+)
+
+// holding 寄存器地址(0-15)，其余地址保留
+const (
+	regAutoControl     = 0 // 0=关闭 1=开启
+	regManualGear      = 1 // 挡位类别索引，对应 gearCategoryOrder
+	regManualLevel     = 2 // 挡位级别索引，对应所在类别下的 0/1/2(低/中/高)
+	regCustomSpeedRPM  = 3 // 自定义转速
+	regTargetTemp      = 4 // 智能控温目标温度
+	regLightBrightness = 5 // 灯带亮度 0-100
+	regLightMode       = 6 // 灯带模式索引，对应 types.LightStripModeOptions
+)
+
+// input 寄存器地址(0-15)，其余地址保留
+const (
+	inRegCurrentRPM        = 0
+	inRegTargetRPM         = 1
+	inRegCPUTemp           = 2
+	inRegGPUTemp           = 3
+	inRegMaxTemp           = 4
+	inRegConnected         = 5
+	inRegSmartControlState = 6
+)
+
+// coil 地址(0-7)，其余地址保留
+const (
+	coilAutoControl             = 0
+	coilGearLight               = 1
+	coilCustomSpeedEnabled      = 2
+	coilIgnoreDeviceOnReconnect = 3
+)
+
+// gearCategoryOrder 挡位类别的固定展示与索引顺序，对应 regManualGear
+var gearCategoryOrder = []string{"静音", "标准", "强劲", "超频"}
+
+// StateProvider 向 Modbus 从站提供需要对外暴露的只读遥测与当前配置
+type StateProvider interface {
+	FanData() types.FanData
+	TemperatureData() types.TemperatureData
+	Config() types.AppConfig
+	Connected() bool
+	SmartControlStateIndex() int
+}
+
+// CommandHandler 接收来自 Modbus 写请求的控制命令；每个方法都必须与 GUI 使用同一条
+// 校验/持久化/日志记录路径，而不是直接修改 AppConfig
+type CommandHandler interface {
+	SetAutoControl(enabled bool) error
+	SetManualGear(category string) error
+	SetManualLevel(level string) error
+	SetCustomSpeedRPM(rpm int) error
+	SetCustomSpeedEnabled(enabled bool) error
+	SetTargetTemp(temp int) error
+	SetLightBrightness(percent int) error
+	SetLightMode(mode string) error
+	SetGearLight(enabled bool) error
+	SetIgnoreDeviceOnReconnect(enabled bool) error
+}
+
+func boolToUint16(b bool) uint16 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func clampUint16(v int) uint16 {
+	if v < 0 {
+		return 0
+	}
+	if v > 0xFFFF {
+		return 0xFFFF
+	}
+	return uint16(v)
+}
+
+func gearCategoryIndex(category string) int {
+	for i, name := range gearCategoryOrder {
+		if name == category {
+			return i
+		}
+	}
+	return -1
+}
+
+// levelIndexFromName 将 AppConfig.ManualLevel("低"/"中"/"高")映射为挡位数组下标；
+// GearCommands 的每个类别都固定按低/中/高顺序排列，因此与具体挡位类别无关
+func levelIndexFromName(level string) int {
+	switch level {
+	case "低":
+		return 0
+	case "中":
+		return 1
+	case "高":
+		return 2
+	default:
+		return -1
+	}
+}
+
+func lightModeIndex(mode string) int {
+	for i, opt := range types.LightStripModeOptions {
+		if opt.Mode == mode {
+			return i
+		}
+	}
+	return -1
+}
+
+// readHoldingRegister 读取单个保持寄存器的当前值，地址越界时返回 false
+func readHoldingRegister(cfg types.AppConfig, addr uint16) (uint16, bool) {
+	if int(addr) >= holdingRegisterCount {
+		return 0, false
+	}
+
+	switch addr {
+	case regAutoControl:
+		return boolToUint16(cfg.AutoControl), true
+	case regManualGear:
+		idx := gearCategoryIndex(cfg.ManualGear)
+		if idx < 0 {
+			return 0, true
+		}
+		return uint16(idx), true
+	case regManualLevel:
+		idx := levelIndexFromName(cfg.ManualLevel)
+		if idx < 0 {
+			return 0, true
+		}
+		return uint16(idx), true
+	case regCustomSpeedRPM:
+		return clampUint16(cfg.CustomSpeedRPM), true
+	case regTargetTemp:
+		return clampUint16(cfg.SmartControl.TargetTemp), true
+	case regLightBrightness:
+		return clampUint16(cfg.LightStrip.Brightness), true
+	case regLightMode:
+		idx := lightModeIndex(cfg.LightStrip.Mode)
+		if idx < 0 {
+			return 0, true
+		}
+		return uint16(idx), true
+	default: // 保留地址，读作 0
+		return 0, true
+	}
+}
+
+// validateHoldingRegisterWrite 校验地址是否可写、取值是否合法，但不调用 CommandHandler；
+// 供多寄存器写入在真正落地前整批校验，避免前面的寄存器已经生效、后面的才报非法
+func validateHoldingRegisterWrite(addr, value uint16) error {
+	if int(addr) >= holdingRegisterCount {
+		return errIllegalAddress
+	}
+
+	switch addr {
+	case regAutoControl, regCustomSpeedRPM, regTargetTemp:
+		return nil
+	case regManualGear:
+		if int(value) >= len(gearCategoryOrder) {
+			return errIllegalValue
+		}
+		return nil
+	case regManualLevel:
+		// 级别索引固定对应所在类别挡位数组中的 0/1/2
+		if int(value) > 2 {
+			return errIllegalValue
+		}
+		return nil
+	case regLightBrightness:
+		if value > 100 {
+			return errIllegalValue
+		}
+		return nil
+	case regLightMode:
+		if int(value) >= len(types.LightStripModeOptions) {
+			return errIllegalValue
+		}
+		return nil
+	default: // 保留地址不可写
+		return errIllegalAddress
+	}
+}
+
+// writeHoldingRegister 将写请求分发给 CommandHandler，地址越界或取值非法时返回错误
+func writeHoldingRegister(handler CommandHandler, addr uint16, value uint16) error {
+	if handler == nil {
+		return errIllegalAddress
+	}
+	if err := validateHoldingRegisterWrite(addr, value); err != nil {
+		return err
+	}
+
+	switch addr {
+	case regAutoControl:
+		return handler.SetAutoControl(value != 0)
+	case regManualGear:
+		return handler.SetManualGear(gearCategoryOrder[value])
+	case regManualLevel:
+		return handler.SetManualLevel(levelNameFromIndex(int(value)))
+	case regCustomSpeedRPM:
+		return handler.SetCustomSpeedRPM(int(value))
+	case regTargetTemp:
+		return handler.SetTargetTemp(int(value))
+	case regLightBrightness:
+		return handler.SetLightBrightness(int(value))
+	case regLightMode:
+		return handler.SetLightMode(types.LightStripModeOptions[value].Mode)
+	default: // 保留地址不可写
+		return errIllegalAddress
+	}
+}
+
+// levelNameFromIndex 将 0/1/2 映射为挡位名称后缀，实际挡位名称由 handler 结合当前类别解析
+func levelNameFromIndex(index int) string {
+	switch index {
+	case 0:
+		return "低"
+	case 1:
+		return "中"
+	case 2:
+		return "高"
+	default:
+		return ""
+	}
+}
+
+// readInputRegister 读取单个输入寄存器的当前遥测值，地址越界时返回 false
+func readInputRegister(state StateProvider, addr uint16) (uint16, bool) {
+	if int(addr) >= inputRegisterCount {
+		return 0, false
+	}
+
+	fan := state.FanData()
+	temp := state.TemperatureData()
+
+	switch addr {
+	case inRegCurrentRPM:
+		return fan.CurrentRPM, true
+	case inRegTargetRPM:
+		return fan.TargetRPM, true
+	case inRegCPUTemp:
+		return clampUint16(temp.CPUTemp), true
+	case inRegGPUTemp:
+		return clampUint16(temp.GPUTemp), true
+	case inRegMaxTemp:
+		return clampUint16(temp.MaxTemp), true
+	case inRegConnected:
+		return boolToUint16(state.Connected()), true
+	case inRegSmartControlState:
+		return clampUint16(state.SmartControlStateIndex()), true
+	default: // 保留地址，读作 0
+		return 0, true
+	}
+}
+
+// readCoil 读取单个线圈的当前值，地址越界时返回 false
+func readCoil(cfg types.AppConfig, addr uint16) (bool, bool) {
+	if int(addr) >= coilCount {
+		return false, false
+	}
+
+	switch addr {
+	case coilAutoControl:
+		return cfg.AutoControl, true
+	case coilGearLight:
+		return cfg.GearLight, true
+	case coilCustomSpeedEnabled:
+		return cfg.CustomSpeedEnabled, true
+	case coilIgnoreDeviceOnReconnect:
+		return cfg.IgnoreDeviceOnReconnect, true
+	default: // 保留地址，读作 false
+		return false, true
+	}
+}
+
+// validateCoilWrite 校验线圈地址是否可写，但不调用 CommandHandler；供多线圈写入在真正
+// 落地前整批校验，避免前面的线圈已经生效、后面的才报非法
+func validateCoilWrite(addr uint16) error {
+	if int(addr) >= coilCount {
+		return errIllegalAddress
+	}
+
+	switch addr {
+	case coilAutoControl, coilGearLight, coilCustomSpeedEnabled, coilIgnoreDeviceOnReconnect:
+		return nil
+	default: // 保留地址不可写
+		return errIllegalAddress
+	}
+}
+
+// writeCoil 将线圈写请求分发给 CommandHandler，地址越界时返回错误
+func writeCoil(handler CommandHandler, addr uint16, value bool) error {
+	if handler == nil {
+		return errIllegalAddress
+	}
+	if err := validateCoilWrite(addr); err != nil {
+		return err
+	}
+
+	switch addr {
+	case coilAutoControl:
+		return handler.SetAutoControl(value)
+	case coilGearLight:
+		return handler.SetGearLight(value)
+	case coilCustomSpeedEnabled:
+		return handler.SetCustomSpeedEnabled(value)
+	case coilIgnoreDeviceOnReconnect:
+		return handler.SetIgnoreDeviceOnReconnect(value)
+	default: // 保留地址不可写
+		return errIllegalAddress
+	}
+}
+
+var (
+	errIllegalAddress = fmt.Errorf("非法数据地址")
+	errIllegalValue   = fmt.Errorf("非法数据值")
+)
@@ -0,0 +1,364 @@
+package modbus
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// Server Modbus TCP 从站，负责接受连接并按功能码读写寄存器/线圈
+type Server struct {
+	logger  types.Logger
+	state   StateProvider
+	handler CommandHandler
+
+	mu       sync.Mutex
+	listener net.Listener
+	running  bool
+
+	allowNets []*net.IPNet
+}
+
+// NewServer 创建一个 Modbus TCP 服务端
+func NewServer(logger types.Logger, state StateProvider, handler CommandHandler) *Server {
+	return &Server{
+		logger:  logger,
+		state:   state,
+		handler: handler,
+	}
+}
+
+// Start 监听 cfg.Bind，cfg.Enabled 为 false 时直接返回不做任何事
+func (s *Server) Start(cfg types.ModbusConfig) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !cfg.Enabled {
+		return nil
+	}
+	if s.running {
+		return nil
+	}
+
+	nets, err := parseCIDRs(cfg.AllowCIDRs)
+	if err != nil {
+		return fmt.Errorf("解析Modbus允许网段失败: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", cfg.Bind)
+	if err != nil {
+		return fmt.Errorf("监听Modbus TCP端口失败: %v", err)
+	}
+
+	s.listener = listener
+	s.allowNets = nets
+	s.running = true
+
+	go s.acceptLoop(listener)
+
+	s.logger.Info("Modbus TCP 服务已启动，监听地址 %s", cfg.Bind)
+	return nil
+}
+
+// Stop 关闭监听器，不再接受新连接；已建立的连接不会被主动断开
+func (s *Server) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.running {
+		return
+	}
+	s.listener.Close()
+	s.running = false
+}
+
+func parseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	if len(cidrs) == 0 {
+		return nil, nil
+	}
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, c := range cidrs {
+		_, ipNet, err := net.ParseCIDR(c)
+		if err != nil {
+			return nil, fmt.Errorf("非法CIDR %q: %v", c, err)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+func (s *Server) isAllowed(addr net.Addr) bool {
+	s.mu.Lock()
+	nets := s.allowNets
+	s.mu.Unlock()
+
+	if len(nets) == 0 {
+		return true
+	}
+
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	for _, n := range nets {
+		if n.Contains(tcpAddr.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) acceptLoop(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		if !s.isAllowed(conn.RemoteAddr()) {
+			s.logger.Debug("Modbus TCP 拒绝不在白名单内的连接: %s", conn.RemoteAddr())
+			conn.Close()
+			continue
+		}
+
+		go s.handleConn(conn)
+	}
+}
+
+func (s *Server) handleConn(conn net.Conn) {
+	defer conn.Close()
+
+	header := make([]byte, mbapHeaderLen+1)
+	for {
+		if _, err := readFull(conn, header); err != nil {
+			return
+		}
+
+		length := binary.BigEndian.Uint16(header[4:6])
+		if length < 2 || length > 253 {
+			return
+		}
+
+		body := make([]byte, length-2)
+		if len(body) > 0 {
+			if _, err := readFull(conn, body); err != nil {
+				return
+			}
+		}
+
+		req, err := parseADU(append(header, body...))
+		if err != nil {
+			s.logger.Debug("Modbus TCP 解析请求失败: %v", err)
+			return
+		}
+
+		resp := s.handleRequest(req)
+		if _, err := conn.Write(resp); err != nil {
+			return
+		}
+	}
+}
+
+func readFull(conn net.Conn, buf []byte) (int, error) {
+	total := 0
+	for total < len(buf) {
+		n, err := conn.Read(buf[total:])
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// handleRequest 按功能码分发请求，返回完整的响应报文(含异常响应)
+func (s *Server) handleRequest(req adu) []byte {
+	switch req.functionCode {
+	case fcReadCoils:
+		return s.handleReadCoils(req)
+	case fcReadHoldingRegisters:
+		return s.handleReadHoldingRegisters(req)
+	case fcReadInputRegisters:
+		return s.handleReadInputRegisters(req)
+	case fcWriteSingleCoil:
+		return s.handleWriteSingleCoil(req)
+	case fcWriteSingleRegister:
+		return s.handleWriteSingleRegister(req)
+	case fcWriteMultipleCoils:
+		return s.handleWriteMultipleCoils(req)
+	case fcWriteMultipleRegs:
+		return s.handleWriteMultipleRegisters(req)
+	default:
+		return encodeException(req, excIllegalFunction)
+	}
+}
+
+func (s *Server) handleReadHoldingRegisters(req adu) []byte {
+	start, count, ok := parseReadRequest(req.data)
+	if !ok {
+		return encodeException(req, excIllegalDataValue)
+	}
+
+	cfg := s.state.Config()
+	data := make([]byte, 1+int(count)*2)
+	data[0] = byte(count) * 2
+	for i := range count {
+		value, ok := readHoldingRegister(cfg, start+i)
+		if !ok {
+			return encodeException(req, excIllegalDataAddress)
+		}
+		binary.BigEndian.PutUint16(data[1+int(i)*2:], value)
+	}
+	return encodeResponse(req, req.functionCode, data)
+}
+
+func (s *Server) handleReadInputRegisters(req adu) []byte {
+	start, count, ok := parseReadRequest(req.data)
+	if !ok {
+		return encodeException(req, excIllegalDataValue)
+	}
+
+	data := make([]byte, 1+int(count)*2)
+	data[0] = byte(count) * 2
+	for i := range count {
+		value, ok := readInputRegister(s.state, start+i)
+		if !ok {
+			return encodeException(req, excIllegalDataAddress)
+		}
+		binary.BigEndian.PutUint16(data[1+int(i)*2:], value)
+	}
+	return encodeResponse(req, req.functionCode, data)
+}
+
+func (s *Server) handleReadCoils(req adu) []byte {
+	start, count, ok := parseReadRequest(req.data)
+	if !ok {
+		return encodeException(req, excIllegalDataValue)
+	}
+
+	cfg := s.state.Config()
+	byteCount := (int(count) + 7) / 8
+	data := make([]byte, 1+byteCount)
+	data[0] = byte(byteCount)
+	for i := range count {
+		value, ok := readCoil(cfg, start+i)
+		if !ok {
+			return encodeException(req, excIllegalDataAddress)
+		}
+		if value {
+			data[1+int(i)/8] |= byte(1) << (i % 8)
+		}
+	}
+	return encodeResponse(req, req.functionCode, data)
+}
+
+func (s *Server) handleWriteSingleRegister(req adu) []byte {
+	if len(req.data) != 4 {
+		return encodeException(req, excIllegalDataValue)
+	}
+	addr := binary.BigEndian.Uint16(req.data[0:2])
+	value := binary.BigEndian.Uint16(req.data[2:4])
+
+	if err := writeHoldingRegister(s.handler, addr, value); err != nil {
+		return encodeException(req, exceptionCodeFor(err))
+	}
+	return encodeResponse(req, req.functionCode, req.data)
+}
+
+// handleWriteMultipleRegisters 先整批校验地址与取值，全部合法后才逐个落地到 CommandHandler，
+// 避免请求中间某个寄存器非法时，前面的寄存器已经生效而客户端却只收到一个失败响应
+func (s *Server) handleWriteMultipleRegisters(req adu) []byte {
+	if len(req.data) < 5 {
+		return encodeException(req, excIllegalDataValue)
+	}
+	start := binary.BigEndian.Uint16(req.data[0:2])
+	count := binary.BigEndian.Uint16(req.data[2:4])
+	byteCount := req.data[4]
+	if int(byteCount) != int(count)*2 || len(req.data) != 5+int(byteCount) {
+		return encodeException(req, excIllegalDataValue)
+	}
+
+	for i := range count {
+		value := binary.BigEndian.Uint16(req.data[5+int(i)*2:])
+		if err := validateHoldingRegisterWrite(start+i, value); err != nil {
+			return encodeException(req, exceptionCodeFor(err))
+		}
+	}
+
+	for i := range count {
+		value := binary.BigEndian.Uint16(req.data[5+int(i)*2:])
+		if err := writeHoldingRegister(s.handler, start+i, value); err != nil {
+			return encodeException(req, exceptionCodeFor(err))
+		}
+	}
+	return encodeResponse(req, req.functionCode, req.data[:4])
+}
+
+func (s *Server) handleWriteSingleCoil(req adu) []byte {
+	if len(req.data) != 4 {
+		return encodeException(req, excIllegalDataValue)
+	}
+	addr := binary.BigEndian.Uint16(req.data[0:2])
+	rawValue := binary.BigEndian.Uint16(req.data[2:4])
+	if rawValue != 0x0000 && rawValue != 0xFF00 {
+		return encodeException(req, excIllegalDataValue)
+	}
+
+	if err := writeCoil(s.handler, addr, rawValue == 0xFF00); err != nil {
+		return encodeException(req, exceptionCodeFor(err))
+	}
+	return encodeResponse(req, req.functionCode, req.data)
+}
+
+// handleWriteMultipleCoils 先整批校验地址，全部合法后才逐个落地到 CommandHandler，避免请求
+// 中间某个线圈非法时，前面的线圈已经生效而客户端却只收到一个失败响应
+func (s *Server) handleWriteMultipleCoils(req adu) []byte {
+	if len(req.data) < 5 {
+		return encodeException(req, excIllegalDataValue)
+	}
+	start := binary.BigEndian.Uint16(req.data[0:2])
+	count := binary.BigEndian.Uint16(req.data[2:4])
+	byteCount := req.data[4]
+	expectedBytes := (int(count) + 7) / 8
+	if int(byteCount) != expectedBytes || len(req.data) != 5+expectedBytes {
+		return encodeException(req, excIllegalDataValue)
+	}
+
+	for i := range count {
+		if err := validateCoilWrite(start + i); err != nil {
+			return encodeException(req, exceptionCodeFor(err))
+		}
+	}
+
+	for i := range count {
+		value := req.data[5+int(i)/8]&(byte(1)<<(i%8)) != 0
+		if err := writeCoil(s.handler, start+i, value); err != nil {
+			return encodeException(req, exceptionCodeFor(err))
+		}
+	}
+	return encodeResponse(req, req.functionCode, req.data[:4])
+}
+
+// parseReadRequest 解析起始地址+数量，数量越界时返回 ok=false
+func parseReadRequest(data []byte) (start uint16, count uint16, ok bool) {
+	if len(data) != 4 {
+		return 0, 0, false
+	}
+	start = binary.BigEndian.Uint16(data[0:2])
+	count = binary.BigEndian.Uint16(data[2:4])
+	if count == 0 || count > 125 {
+		return 0, 0, false
+	}
+	return start, count, true
+}
+
+// exceptionCodeFor 将 CommandHandler/寄存器查找返回的错误映射为 Modbus 异常码
+func exceptionCodeFor(err error) byte {
+	if err == errIllegalValue {
+		return excIllegalDataValue
+	}
+	return excIllegalDataAddress
+}
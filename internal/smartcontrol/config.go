@@ -68,6 +68,90 @@ func NormalizeConfig(cfg types.SmartControlConfig, curve []types.FanCurvePoint)
 		cfg.MaxLearnOffset = defaults.MaxLearnOffset
 		changed = true
 	}
+	if cfg.RegularizationScheme < 0 || cfg.RegularizationScheme > 2 {
+		cfg.RegularizationScheme = defaults.RegularizationScheme
+		changed = true
+	}
+	if cfg.OffsetOptimizer < 0 || cfg.OffsetOptimizer > 3 {
+		cfg.OffsetOptimizer = defaults.OffsetOptimizer
+		changed = true
+	}
+	if cfg.RegularizationFactor < 0 || cfg.RegularizationFactor > 50 {
+		cfg.RegularizationFactor = defaults.RegularizationFactor
+		changed = true
+	}
+	if cfg.AdaDeltaRho <= 0 || cfg.AdaDeltaRho >= 1 {
+		cfg.AdaDeltaRho = defaults.AdaDeltaRho
+		changed = true
+	}
+	if cfg.AdaDeltaEpsilon <= 0 || cfg.AdaDeltaEpsilon > 1 {
+		cfg.AdaDeltaEpsilon = defaults.AdaDeltaEpsilon
+		changed = true
+	}
+	if resized, updated := resizeFloatSlice(cfg.AdaDeltaEgHeat, len(curve)); updated {
+		cfg.AdaDeltaEgHeat = resized
+		changed = true
+	}
+	if resized, updated := resizeFloatSlice(cfg.AdaDeltaEgCool, len(curve)); updated {
+		cfg.AdaDeltaEgCool = resized
+		changed = true
+	}
+	if resized, updated := resizeFloatSlice(cfg.AdaDeltaDxHeat, len(curve)); updated {
+		cfg.AdaDeltaDxHeat = resized
+		changed = true
+	}
+	if resized, updated := resizeFloatSlice(cfg.AdaDeltaDxCool, len(curve)); updated {
+		cfg.AdaDeltaDxCool = resized
+		changed = true
+	}
+	if resized, updated := resizeThermalModel(cfg.ThermalModel, curve); updated {
+		cfg.ThermalModel = resized
+		changed = true
+	}
+	if cfg.SmoothingMode < 0 || cfg.SmoothingMode > 1 {
+		cfg.SmoothingMode = defaults.SmoothingMode
+		changed = true
+	}
+	if cfg.GPLengthScale <= 0 || cfg.GPLengthScale > 50 {
+		cfg.GPLengthScale = defaults.GPLengthScale
+		changed = true
+	}
+	if cfg.GPNoise < 0 || cfg.GPNoise > 50 {
+		cfg.GPNoise = defaults.GPNoise
+		changed = true
+	}
+	if cfg.LearnHistorySize < 0 || cfg.LearnHistorySize > 5000 {
+		cfg.LearnHistorySize = defaults.LearnHistorySize
+		changed = true
+	}
+	if cfg.LearnMode < 0 || cfg.LearnMode > 1 {
+		cfg.LearnMode = defaults.LearnMode
+		changed = true
+	}
+	if cfg.SteadyStateDwell < 1 || cfg.SteadyStateDwell > 600 {
+		cfg.SteadyStateDwell = defaults.SteadyStateDwell
+		changed = true
+	}
+	if cfg.OverheatDelta < 1 || cfg.OverheatDelta > 40 {
+		cfg.OverheatDelta = defaults.OverheatDelta
+		changed = true
+	}
+	if cfg.Scheduler != "" && cfg.Scheduler != "legacy" {
+		cfg.Scheduler = defaults.Scheduler
+		changed = true
+	}
+	if cfg.QuietRPMThreshold < 500 || cfg.QuietRPMThreshold > 4000 {
+		cfg.QuietRPMThreshold = defaults.QuietRPMThreshold
+		changed = true
+	}
+	if cfg.PredictiveMode < 0 || cfg.PredictiveMode > 1 {
+		cfg.PredictiveMode = defaults.PredictiveMode
+		changed = true
+	}
+	if cfg.PredictionHorizon < 5 || cfg.PredictionHorizon > 300 {
+		cfg.PredictionHorizon = defaults.PredictionHorizon
+		changed = true
+	}
 
 	if len(cfg.LearnedOffsets) != len(curve) {
 		newOffsets := make([]int, len(curve))
@@ -86,6 +170,10 @@ func NormalizeConfig(cfg types.SmartControlConfig, curve []types.FanCurvePoint)
 		cfg.LearnedOffsetsHeat = newHeatOffsets
 		changed = true
 	}
+	if decayed, updated := decayOffsetsToward(cfg.LearnedOffsetsHeat, cfg.RegularizationScheme, cfg.RegularizationFactor); updated {
+		cfg.LearnedOffsetsHeat = decayed
+		changed = true
+	}
 	if sanitized, updated := constrainOffsetsToCurveBounds(cfg.LearnedOffsetsHeat, curve, cfg.MaxLearnOffset); updated {
 		cfg.LearnedOffsetsHeat = sanitized
 		changed = true
@@ -101,11 +189,32 @@ func NormalizeConfig(cfg types.SmartControlConfig, curve []types.FanCurvePoint)
 		cfg.LearnedOffsetsCool = newCoolOffsets
 		changed = true
 	}
+	if decayed, updated := decayOffsetsToward(cfg.LearnedOffsetsCool, cfg.RegularizationScheme, cfg.RegularizationFactor); updated {
+		cfg.LearnedOffsetsCool = decayed
+		changed = true
+	}
 	if sanitized, updated := constrainOffsetsToCurveBounds(cfg.LearnedOffsetsCool, curve, cfg.MaxLearnOffset); updated {
 		cfg.LearnedOffsetsCool = sanitized
 		changed = true
 	}
 
+	if resized, updated := resizeIntSlice(cfg.MomentumHeat, len(curve)); updated {
+		cfg.MomentumHeat = resized
+		changed = true
+	}
+	if resized, updated := resizeIntSlice(cfg.MomentumCool, len(curve)); updated {
+		cfg.MomentumCool = resized
+		changed = true
+	}
+	if resized, updated := resizeIntSlice(cfg.VarianceHeat, len(curve)); updated {
+		cfg.VarianceHeat = resized
+		changed = true
+	}
+	if resized, updated := resizeIntSlice(cfg.VarianceCool, len(curve)); updated {
+		cfg.VarianceCool = resized
+		changed = true
+	}
+
 	if normalized, updated := normalizeRateBiases(cfg.LearnedRateHeat, cfg.MaxLearnOffset); updated {
 		cfg.LearnedRateHeat = normalized
 		changed = true
@@ -155,3 +264,36 @@ func BlendOffsets(heatOffsets, coolOffsets []int) []int {
 
 	return blended
 }
+
+// decayOffsetsToward 在每次归一化时将学习偏移向零收缩一步，复用 regularizationTerm
+// 的 L1/L2 公式；独立于梯度学习更新，使过时的学习结果在温度环境变化后逐渐被遗忘
+func decayOffsetsToward(offsets []int, scheme, factor int) ([]int, bool) {
+	if factor <= 0 || scheme <= 0 {
+		return offsets, false
+	}
+
+	changed := false
+	decayed := offsets
+	for i, offset := range offsets {
+		if offset == 0 {
+			continue
+		}
+		shrink := regularizationTerm(offset, scheme, factor)
+		if shrink == 0 {
+			continue
+		}
+		newValue := offset - shrink
+		if signInt(newValue) != signInt(offset) {
+			newValue = 0 // 避免单步收缩越过零点造成符号翻转震荡
+		}
+		if newValue == offset {
+			continue
+		}
+		if !changed {
+			decayed = append([]int(nil), offsets...)
+			changed = true
+		}
+		decayed[i] = newValue
+	}
+	return decayed, changed
+}
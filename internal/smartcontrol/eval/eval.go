@@ -0,0 +1,169 @@
+// Package eval 记录智能控温学习步骤的历史样本，供用户离线评估学习效果。
+package eval
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sync"
+)
+
+// Sample 一次 LearnCurveOffsets 调用的结构化采样
+type Sample struct {
+	AvgTemp    int // 当前平均温度(°C)
+	TargetTemp int // 目标温度(°C)
+	TempDelta  int // 与上次采样的温差
+	TargetRPM  int // 计算得到的目标转速
+	BucketIdx  int // 命中的曲线点下标
+	Raw        int // 学习算法计算出的原始评分
+	Delta      int // 实际施加到偏移上的增量
+	Hysteresis int // 记录时刻的滞回带宽(°C)
+}
+
+// Metrics 学习质量的聚合指标，类似一份精简的混淆矩阵
+type Metrics struct {
+	SampleCount      int     // 参与统计的样本数
+	WithinHysteresis float64 // |avgTemp-targetTemp| <= Hysteresis 的样本占比
+	CorrectSignRatio float64 // 施加的 delta 方向与后续温度误差变化方向一致的占比
+	RMSTempError     float64 // 温度误差的均方根
+	RMSRPMChange     float64 // 目标转速变化量的均方根
+}
+
+// Evaluator 固定容量的环形缓冲区评估器
+type Evaluator struct {
+	mu       sync.Mutex
+	samples  []Sample
+	capacity int
+	next     int
+	count    int
+}
+
+// NewEvaluator 创建一个容量为 capacity 的评估器，capacity<=0 时不记录任何样本
+func NewEvaluator(capacity int) *Evaluator {
+	if capacity < 0 {
+		capacity = 0
+	}
+	return &Evaluator{
+		samples:  make([]Sample, capacity),
+		capacity: capacity,
+	}
+}
+
+// Record 追加一个采样，环形缓冲区满后覆盖最旧的样本
+func (e *Evaluator) Record(sample Sample) {
+	if e.capacity == 0 {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.samples[e.next] = sample
+	e.next = (e.next + 1) % e.capacity
+	if e.count < e.capacity {
+		e.count++
+	}
+}
+
+// ordered 返回按采样先后顺序排列的样本快照
+func (e *Evaluator) ordered() []Sample {
+	if e.count == 0 {
+		return nil
+	}
+	ordered := make([]Sample, e.count)
+	start := 0
+	if e.count == e.capacity {
+		start = e.next
+	}
+	for i := range e.count {
+		ordered[i] = e.samples[(start+i)%e.capacity]
+	}
+	return ordered
+}
+
+// Metrics 计算当前缓冲区内样本的聚合质量指标
+func (e *Evaluator) Metrics() Metrics {
+	e.mu.Lock()
+	samples := e.ordered()
+	e.mu.Unlock()
+
+	if len(samples) == 0 {
+		return Metrics{}
+	}
+
+	var withinCount, signMatches, signTotal int
+	var sqTempErrorSum, sqRPMChangeSum float64
+
+	for i, s := range samples {
+		errorTemp := s.AvgTemp - s.TargetTemp
+		if abs(errorTemp) <= s.Hysteresis {
+			withinCount++
+		}
+		sqTempErrorSum += float64(errorTemp) * float64(errorTemp)
+
+		if i+1 < len(samples) {
+			next := samples[i+1]
+			nextError := next.AvgTemp - next.TargetTemp
+			improved := abs(nextError) < abs(errorTemp)
+			if s.Delta != 0 {
+				signTotal++
+				wantsCooling := errorTemp > 0
+				appliedCooling := s.Delta > 0 // 偏移增大 -> 转速提高 -> 倾向降温
+				if improved && wantsCooling == appliedCooling {
+					signMatches++
+				}
+			}
+			sqRPMChangeSum += float64(next.TargetRPM-s.TargetRPM) * float64(next.TargetRPM-s.TargetRPM)
+		}
+	}
+
+	metrics := Metrics{
+		SampleCount:      len(samples),
+		WithinHysteresis: float64(withinCount) / float64(len(samples)),
+		RMSTempError:     math.Sqrt(sqTempErrorSum / float64(len(samples))),
+	}
+	if signTotal > 0 {
+		metrics.CorrectSignRatio = float64(signMatches) / float64(signTotal)
+	}
+	if len(samples) > 1 {
+		metrics.RMSRPMChange = math.Sqrt(sqRPMChangeSum / float64(len(samples)-1))
+	}
+
+	return metrics
+}
+
+// DumpLibSVM 以 libsvm 格式导出样本，供离线训练替代控制器使用。
+// label 为温度误差的符号(1/-1/0)，特征为归一化后的温度、转速、delta 与温度变化趋势。
+func (e *Evaluator) DumpLibSVM(w io.Writer) error {
+	e.mu.Lock()
+	samples := e.ordered()
+	e.mu.Unlock()
+
+	for _, s := range samples {
+		errorTemp := s.AvgTemp - s.TargetTemp
+		label := 0
+		switch {
+		case errorTemp > 0:
+			label = 1
+		case errorTemp < 0:
+			label = -1
+		}
+
+		normTemp := float64(s.AvgTemp) / 100.0
+		normRPM := float64(s.TargetRPM) / 4000.0
+		normDelta := float64(s.Delta) / 100.0
+		normTrend := float64(s.TempDelta) / 10.0
+
+		if _, err := fmt.Fprintf(w, "%d 1:%.6f 2:%.6f 3:%.6f 4:%.6f\n", label, normTemp, normRPM, normDelta, normTrend); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -48,6 +48,24 @@ func constrainOffsetsToCurveBounds(offsets []int, curve []types.FanCurvePoint, m
 	return normalized, updated
 }
 
+func resizeFloatSlice(values []float64, size int) ([]float64, bool) {
+	if len(values) == size {
+		return values, false
+	}
+	resized := make([]float64, size)
+	copy(resized, values)
+	return resized, true
+}
+
+func resizeIntSlice(values []int, size int) ([]int, bool) {
+	if len(values) == size {
+		return values, false
+	}
+	resized := make([]int, size)
+	copy(resized, values)
+	return resized, true
+}
+
 func intSlicesEqual(a, b []int) bool {
 	if len(a) != len(b) {
 		return false
@@ -78,6 +96,21 @@ func nearestCurveIndex(temp int, curve []types.FanCurvePoint) int {
 	return idx
 }
 
+// candidateRPMsFromDeltas 以 pivot 为基准按 deltas 偏移生成候选转速，裁剪到曲线边界并去重
+func candidateRPMsFromDeltas(pivot int, deltas []int, minRPM, maxRPM int) []int {
+	candidates := make([]int, 0, len(deltas))
+	seen := make(map[int]bool, len(deltas))
+	for _, delta := range deltas {
+		rpm := clampInt(pivot+delta, minRPM, maxRPM)
+		if seen[rpm] {
+			continue
+		}
+		seen[rpm] = true
+		candidates = append(candidates, rpm)
+	}
+	return candidates
+}
+
 func clampInt(value, minValue, maxValue int) int {
 	if value < minValue {
 		return minValue
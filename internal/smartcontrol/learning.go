@@ -1,13 +1,23 @@
 package smartcontrol
 
-import "github.com/TIANLI0/BS2PRO-Controller/internal/types"
+import (
+	"math"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
 
 // LearnCurveOffsets 学习并更新曲线偏移
-func LearnCurveOffsets(avgTemp, lastAvgTemp, targetRPM, lastTargetRPM int, recentAvgTemps []int, curve []types.FanCurvePoint, cfg types.SmartControlConfig) ([]int, []int, []int, []int, bool) {
+// 除学习偏移外，还返回随 idx 演化的 AdaDelta 累积量 (E[g²]/E[Δx²]) 以及 Momentum/Adam 优化器的
+// 一阶/二阶矩累积量，调用方应与偏移一起持久化。最后三个 int 是本次学习 tick 命中的曲线点下标、
+// 算出的原始评分与实际施加到偏移上的增量，供调用方喂给 eval.Evaluator 做离线评估；曲线为空、
+// 样本窗口不足或不稳定而提前返回时三者均为 0；|raw|<4 跳过本次学习时仍返回 idx 与 raw，增量为 0。
+func LearnCurveOffsets(avgTemp, lastAvgTemp, targetRPM, lastTargetRPM int, recentAvgTemps []int, curve []types.FanCurvePoint, cfg types.SmartControlConfig) ([]int, []int, []int, []int, []float64, []float64, []float64, []float64, []int, []int, []int, []int, int, int, int, bool) {
 	if len(curve) == 0 {
 		rateHeat, _ := normalizeRateBiases(cfg.LearnedRateHeat, cfg.MaxLearnOffset)
 		rateCool, _ := normalizeRateBiases(cfg.LearnedRateCool, cfg.MaxLearnOffset)
-		return cfg.LearnedOffsetsHeat, cfg.LearnedOffsetsCool, rateHeat, rateCool, false
+		return cfg.LearnedOffsetsHeat, cfg.LearnedOffsetsCool, rateHeat, rateCool,
+			cfg.AdaDeltaEgHeat, cfg.AdaDeltaEgCool, cfg.AdaDeltaDxHeat, cfg.AdaDeltaDxCool,
+			cfg.MomentumHeat, cfg.MomentumCool, cfg.VarianceHeat, cfg.VarianceCool, 0, 0, 0, false
 	}
 
 	heatOffsets := make([]int, len(curve))
@@ -15,6 +25,16 @@ func LearnCurveOffsets(avgTemp, lastAvgTemp, targetRPM, lastTargetRPM int, recen
 	copy(heatOffsets, cfg.LearnedOffsetsHeat)
 	copy(coolOffsets, cfg.LearnedOffsetsCool)
 
+	egHeat, _ := resizeFloatSlice(cfg.AdaDeltaEgHeat, len(curve))
+	egCool, _ := resizeFloatSlice(cfg.AdaDeltaEgCool, len(curve))
+	dxHeat, _ := resizeFloatSlice(cfg.AdaDeltaDxHeat, len(curve))
+	dxCool, _ := resizeFloatSlice(cfg.AdaDeltaDxCool, len(curve))
+
+	momentumHeat, _ := resizeIntSlice(cfg.MomentumHeat, len(curve))
+	momentumCool, _ := resizeIntSlice(cfg.MomentumCool, len(curve))
+	varianceHeat, _ := resizeIntSlice(cfg.VarianceHeat, len(curve))
+	varianceCool, _ := resizeIntSlice(cfg.VarianceCool, len(curve))
+
 	if len(heatOffsets) != len(curve) {
 		heatOffsets = make([]int, len(curve))
 		copy(heatOffsets, cfg.LearnedOffsets)
@@ -31,7 +51,7 @@ func LearnCurveOffsets(avgTemp, lastAvgTemp, targetRPM, lastTargetRPM int, recen
 	learningDelay := max(1, cfg.LearnDelay)
 	minRequired := learningWindow + learningDelay
 	if len(recentAvgTemps) < minRequired {
-		return heatOffsets, coolOffsets, rateHeat, rateCool, false
+		return heatOffsets, coolOffsets, rateHeat, rateCool, egHeat, egCool, dxHeat, dxCool, momentumHeat, momentumCool, varianceHeat, varianceCool, 0, 0, 0, false
 	}
 
 	windowStart := len(recentAvgTemps) - minRequired
@@ -40,7 +60,7 @@ func LearnCurveOffsets(avgTemp, lastAvgTemp, targetRPM, lastTargetRPM int, recen
 	if !isStableLearningWindow(learningWindowTemps, cfg.Hysteresis+1) {
 		overheatMargin := cfg.TargetTemp + cfg.Hysteresis + 3
 		if avgTemp < overheatMargin {
-			return heatOffsets, coolOffsets, rateHeat, rateCool, false
+			return heatOffsets, coolOffsets, rateHeat, rateCool, egHeat, egCool, dxHeat, dxCool, momentumHeat, momentumCool, varianceHeat, varianceCool, 0, 0, 0, false
 		}
 	}
 
@@ -89,47 +109,58 @@ func LearnCurveOffsets(avgTemp, lastAvgTemp, targetRPM, lastTargetRPM int, recen
 	}
 
 	if absInt(raw) < 4 {
-		return heatOffsets, coolOffsets, rateHeat, rateCool, false
+		return heatOffsets, coolOffsets, rateHeat, rateCool, egHeat, egCool, dxHeat, dxCool, momentumHeat, momentumCool, varianceHeat, varianceCool, idx, raw, 0, false
 	}
 
-	// 将评分压缩为小步进，避免学习曲线过于陡峭。
-	denominator := max(10, 24-cfg.LearnRate*2)
-	delta := raw / denominator
-	if delta == 0 {
-		delta = signInt(raw)
-	}
-	delta = clampInt(delta, -4, 6)
-
 	activeOffsets := &coolOffsets
 	passiveOffsets := &heatOffsets
 	activeRate := &rateCool
 	passiveRate := &rateHeat
+	activeEg := egCool
+	activeDx := dxCool
+	activeMomentum := momentumCool
+	activeVariance := varianceCool
 	if tempDelta >= 0 {
 		activeOffsets = &heatOffsets
 		passiveOffsets = &coolOffsets
 		activeRate = &rateHeat
 		passiveRate = &rateCool
+		activeEg = egHeat
+		activeDx = dxHeat
+		activeMomentum = momentumHeat
+		activeVariance = varianceHeat
 	}
 	rateIdx := rateBucketIndex(tempDelta)
 
+	// 按 cfg.OffsetOptimizer 选择偏移学习的优化器；默认(AdaDelta)按分桶维护梯度平方与更新量
+	// 平方的滑动平均，自适应每个曲线点的有效学习率，避免温差大的桶震荡、安静桶学习过慢。
+	// SGD/Momentum/Adam 是供高 Aggressiveness+LearnRate 场景抑制震荡的替代优化器。
+	var delta int
+	if optimizer := offsetOptimizerFor(cfg.OffsetOptimizer); optimizer != nil {
+		delta = optimizer.Step(idx, raw, cfg.LearnRate, activeMomentum, activeVariance)
+	} else {
+		delta = adaDeltaStep(activeEg, activeDx, idx, raw, cfg.AdaDeltaRho, cfg.AdaDeltaEpsilon)
+	}
+	delta = clampInt(delta, -4, 6)
+
 	changed := false
-	if applyDeltaAtIndex(*activeOffsets, idx, delta, curve, cfg.MaxLearnOffset) {
+	if applyDeltaAtIndex(*activeOffsets, idx, delta, curve, cfg) {
 		changed = true
 	}
-	if applyDeltaAtIndex(*activeOffsets, idx-1, scaledDelta(delta, 2, 3), curve, cfg.MaxLearnOffset) {
+	if applyDeltaAtIndex(*activeOffsets, idx-1, scaledDelta(delta, 2, 3), curve, cfg) {
 		changed = true
 	}
-	if applyDeltaAtIndex(*activeOffsets, idx+1, scaledDelta(delta, 2, 3), curve, cfg.MaxLearnOffset) {
+	if applyDeltaAtIndex(*activeOffsets, idx+1, scaledDelta(delta, 2, 3), curve, cfg) {
 		changed = true
 	}
-	if applyDeltaAtIndex(*activeOffsets, idx-2, scaledDelta(delta, 1, 3), curve, cfg.MaxLearnOffset) {
+	if applyDeltaAtIndex(*activeOffsets, idx-2, scaledDelta(delta, 1, 3), curve, cfg) {
 		changed = true
 	}
-	if applyDeltaAtIndex(*activeOffsets, idx+2, scaledDelta(delta, 1, 3), curve, cfg.MaxLearnOffset) {
+	if applyDeltaAtIndex(*activeOffsets, idx+2, scaledDelta(delta, 1, 3), curve, cfg) {
 		changed = true
 	}
 
-	if applyDeltaAtIndex(*passiveOffsets, idx, scaledDelta(delta, 1, 8), curve, cfg.MaxLearnOffset) {
+	if applyDeltaAtIndex(*passiveOffsets, idx, scaledDelta(delta, 1, 8), curve, cfg) {
 		changed = true
 	}
 
@@ -140,23 +171,23 @@ func LearnCurveOffsets(avgTemp, lastAvgTemp, targetRPM, lastTargetRPM int, recen
 	}
 	rateDelta = clampInt(rateDelta, -3, 4)
 
-	if applyRateBiasDeltaAtIndex(*activeRate, rateIdx, rateDelta, cfg.MaxLearnOffset) {
+	if applyRateBiasDeltaAtIndex(*activeRate, rateIdx, rateDelta, cfg) {
 		changed = true
 	}
-	if applyRateBiasDeltaAtIndex(*activeRate, rateIdx-1, scaledDelta(rateDelta, 2, 3), cfg.MaxLearnOffset) {
+	if applyRateBiasDeltaAtIndex(*activeRate, rateIdx-1, scaledDelta(rateDelta, 2, 3), cfg) {
 		changed = true
 	}
-	if applyRateBiasDeltaAtIndex(*activeRate, rateIdx+1, scaledDelta(rateDelta, 2, 3), cfg.MaxLearnOffset) {
+	if applyRateBiasDeltaAtIndex(*activeRate, rateIdx+1, scaledDelta(rateDelta, 2, 3), cfg) {
 		changed = true
 	}
-	if applyRateBiasDeltaAtIndex(*passiveRate, rateIdx, scaledDelta(rateDelta, 1, 8), cfg.MaxLearnOffset) {
+	if applyRateBiasDeltaAtIndex(*passiveRate, rateIdx, scaledDelta(rateDelta, 1, 8), cfg) {
 		changed = true
 	}
 
-	if smoothAndClampOffsets(*activeOffsets, curve, cfg.MaxLearnOffset) {
+	if smoothAndClampOffsets(*activeOffsets, curve, cfg) {
 		changed = true
 	}
-	if smoothAndClampOffsets(*passiveOffsets, curve, cfg.MaxLearnOffset) {
+	if smoothAndClampOffsets(*passiveOffsets, curve, cfg) {
 		changed = true
 	}
 	if smoothRateBiases(*activeRate, cfg.MaxLearnOffset) {
@@ -166,9 +197,32 @@ func LearnCurveOffsets(avgTemp, lastAvgTemp, targetRPM, lastTargetRPM int, recen
 		changed = true
 	}
 
-	return heatOffsets, coolOffsets, rateHeat, rateCool, changed
+	return heatOffsets, coolOffsets, rateHeat, rateCool, egHeat, egCool, dxHeat, dxCool,
+		momentumHeat, momentumCool, varianceHeat, varianceCool, idx, raw, delta, changed
+}
+
+// adaDeltaStep 对 idx 处的累积量执行一次 AdaDelta 更新，返回取整后的位移量。
+func adaDeltaStep(eg, dx []float64, idx, gradient int, rho, epsilon float64) int {
+	if idx < 0 || idx >= len(eg) || idx >= len(dx) {
+		return 0
+	}
+
+	g := float64(gradient)
+	eg[idx] = rho*eg[idx] + (1-rho)*g*g
+
+	step := -math.Sqrt(dx[idx]+epsilon) / math.Sqrt(eg[idx]+epsilon) * g
+	dx[idx] = rho*dx[idx] + (1-rho)*step*step
+
+	return int(math.Round(-step))
 }
 
+// rateBucketMin/rateBucketMax 给温升/温降速率(℃/tick)分桶的范围，共 7 个桶，
+// 与 GetDefaultSmartControlConfig 里 LearnedRateHeat/Cool 的出厂长度一致
+const (
+	rateBucketMin = -3
+	rateBucketMax = 3
+)
+
 func rateBucketCount() int {
 	return rateBucketMax - rateBucketMin + 1
 }
@@ -204,7 +258,7 @@ func normalizeRateBiases(rateBiases []int, maxLearnOffset int) ([]int, bool) {
 	return normalized, changed
 }
 
-func applyDeltaAtIndex(offsets []int, idx, delta int, curve []types.FanCurvePoint, maxLearnOffset int) bool {
+func applyDeltaAtIndex(offsets []int, idx, delta int, curve []types.FanCurvePoint, cfg types.SmartControlConfig) bool {
 	if delta == 0 || idx < 0 || idx >= len(offsets) {
 		return false
 	}
@@ -212,7 +266,7 @@ func applyDeltaAtIndex(offsets []int, idx, delta int, curve []types.FanCurvePoin
 		return false
 	}
 	leftMinRPM, rightMaxRPM := getCurveEdgeRPMBounds(curve)
-	newValue := clampOffsetForPoint(offsets[idx]+delta, curve[idx].RPM, leftMinRPM, rightMaxRPM, maxLearnOffset)
+	newValue := clampOffsetForPoint(offsets[idx]+delta, curve[idx].RPM, leftMinRPM, rightMaxRPM, cfg.MaxLearnOffset)
 	if newValue == offsets[idx] {
 		return false
 	}
@@ -220,11 +274,29 @@ func applyDeltaAtIndex(offsets []int, idx, delta int, curve []types.FanCurvePoin
 	return true
 }
 
-func applyRateBiasDeltaAtIndex(rateBiases []int, idx, delta, maxLearnOffset int) bool {
+// regularizationTerm 计算向零收缩的正则化项，供 decayOffsetsToward 在每次归一化时使用；
+// 学习更新本身(applyDeltaAtIndex/applyRateBiasDeltaAtIndex)不再重复应用，避免同一因子被
+// 两个不同频率的调用点各自全量扣减。
+// scheme: 0=无 1=L1(按符号收缩固定步长) 2=L2(按比例收缩)
+func regularizationTerm(offset, scheme, factor int) int {
+	if factor <= 0 || offset == 0 {
+		return 0
+	}
+	switch scheme {
+	case 1:
+		return factor * signInt(offset)
+	case 2:
+		return 2 * factor * offset / 100
+	default:
+		return 0
+	}
+}
+
+func applyRateBiasDeltaAtIndex(rateBiases []int, idx, delta int, cfg types.SmartControlConfig) bool {
 	if delta == 0 || idx < 0 || idx >= len(rateBiases) {
 		return false
 	}
-	newValue := clampRateBias(rateBiases[idx]+delta, maxLearnOffset)
+	newValue := clampRateBias(rateBiases[idx]+delta, cfg.MaxLearnOffset)
 	if newValue == rateBiases[idx] {
 		return false
 	}
@@ -257,26 +329,32 @@ func signInt(value int) int {
 	return 0
 }
 
-func smoothAndClampOffsets(offsets []int, curve []types.FanCurvePoint, maxLearnOffset int) bool {
+func smoothAndClampOffsets(offsets []int, curve []types.FanCurvePoint, cfg types.SmartControlConfig) bool {
 	if len(offsets) == 0 || len(curve) == 0 {
 		return false
 	}
 	changed := false
 	leftMinRPM, rightMaxRPM := getCurveEdgeRPMBounds(curve)
-
-	smoothed := make([]int, len(offsets))
-	for i := range offsets {
-		weighted := offsets[i] * 5
-		weight := 5
-		if i > 0 {
-			weighted += offsets[i-1]
-			weight++
-		}
-		if i+1 < len(offsets) {
-			weighted += offsets[i+1]
-			weight++
+	maxLearnOffset := cfg.MaxLearnOffset
+
+	var smoothed []int
+	if cfg.SmoothingMode == 1 {
+		smoothed = GPSmoothOffsets(offsets, curve, cfg.GPLengthScale, cfg.GPNoise)
+	} else {
+		smoothed = make([]int, len(offsets))
+		for i := range offsets {
+			weighted := offsets[i] * 5
+			weight := 5
+			if i > 0 {
+				weighted += offsets[i-1]
+				weight++
+			}
+			if i+1 < len(offsets) {
+				weighted += offsets[i+1]
+				weight++
+			}
+			smoothed[i] = weighted / weight
 		}
-		smoothed[i] = weighted / weight
 	}
 
 	maxJump := min(max(20, maxLearnOffset/10), 90)
@@ -0,0 +1,69 @@
+package smartcontrol
+
+import (
+	"testing"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// TestDecayOffsetsTowardConvergesToZero 验证 decayOffsetsToward 在 L1/L2 两种方案下，
+// 对不受任何新学习增量影响的偏移反复归一化后最终会收缩回 0(对应"零温度误差"场景下
+// applyDeltaAtIndex 不再产生非零 delta，decayOffsetsToward 是唯一还在收缩偏移的地方)。
+func TestDecayOffsetsTowardConvergesToZero(t *testing.T) {
+	cases := []struct {
+		name   string
+		scheme int
+		factor int
+	}{
+		{name: "L1", scheme: 1, factor: 5},
+		{name: "L2", scheme: 2, factor: 50},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			offsets := []int{600, -600, 150, -1, 0}
+
+			const maxIterations = 500
+			iterations := 0
+			for {
+				decayed, changed := decayOffsetsToward(offsets, tc.scheme, tc.factor)
+				offsets = decayed
+				iterations++
+				if !changed {
+					break
+				}
+				if iterations >= maxIterations {
+					t.Fatalf("did not converge within %d iterations, offsets=%v", maxIterations, offsets)
+				}
+			}
+
+			for i, v := range offsets {
+				if v != 0 {
+					t.Errorf("offsets[%d] = %d, want 0 after convergence", i, v)
+				}
+			}
+		})
+	}
+}
+
+// TestApplyDeltaAtIndexNoLongerRegularizes 确认学习更新(applyDeltaAtIndex)不再重复应用
+// regularizationTerm：当 delta 恰好等于期望的曲线点跳变量时，新值应精确落在该跳变量上，
+// 不会被再额外收缩一次(收缩现在只发生在 decayOffsetsToward/NormalizeConfig 里)。
+func TestApplyDeltaAtIndexNoLongerRegularizes(t *testing.T) {
+	curve := []types.FanCurvePoint{
+		{Temperature: 40, RPM: 1000},
+		{Temperature: 60, RPM: 2000},
+		{Temperature: 80, RPM: 3000},
+	}
+	cfg := types.GetDefaultSmartControlConfig(curve)
+	cfg.RegularizationScheme = 2
+	cfg.RegularizationFactor = 50
+
+	offsets := []int{200, 0, 0}
+	if !applyDeltaAtIndex(offsets, 0, 10, curve, cfg) {
+		t.Fatalf("expected applyDeltaAtIndex to report a change")
+	}
+	if offsets[0] != 210 {
+		t.Errorf("offsets[0] = %d, want 210 (delta applied without extra regularization shrink)", offsets[0])
+	}
+}
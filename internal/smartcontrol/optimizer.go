@@ -0,0 +1,96 @@
+package smartcontrol
+
+// Q10 定点常量: beta1/beta2/eps 按 ×1024 缩放为整数，避免在整数学习主链路里引入浮点
+const (
+	fixedPointShift = 10
+	fixedPointScale = 1 << fixedPointShift // 1024
+	beta1Q          = 922                  // beta1=0.9
+	beta2Q          = 1023                 // beta2=0.999
+	epsQ            = fixedPointScale      // eps=1
+)
+
+// offsetOptimizer 枚举值，对应 types.SmartControlConfig.OffsetOptimizer
+const (
+	optimizerAdaDelta = 0 // 默认，延续既有行为，见 adaDeltaStep
+	optimizerSGD      = 1
+	optimizerMomentum = 2
+	optimizerAdam     = 3
+)
+
+// OffsetOptimizer 将原始梯度信号(raw)转换为施加在某个曲线分桶上的偏移增量(RPM)。
+// idx 是该增量对应的曲线分桶下标，momentum/variance 是按分桶持久化的一阶/二阶矩累积量，
+// 与 raw 同单位的整数(variance 为 raw 的平方单位)；仅衰减系数 beta1/beta2 按 Q10 定点表示。
+type OffsetOptimizer interface {
+	Step(idx, raw, learnRate int, momentum, variance []int) int
+}
+
+// SGDOptimizer 不维护任何状态，直接按 learnRate 缩放原始梯度: raw / sgdDenominator(learnRate)
+type SGDOptimizer struct{}
+
+func (SGDOptimizer) Step(idx, raw, learnRate int, momentum, variance []int) int {
+	return raw / sgdDenominator(learnRate)
+}
+
+// MomentumOptimizer 对梯度做指数滑动平均后再缩放，抑制 Aggressiveness/LearnRate 都偏高时的来回震荡
+type MomentumOptimizer struct{}
+
+func (MomentumOptimizer) Step(idx, raw, learnRate int, momentum, variance []int) int {
+	if idx < 0 || idx >= len(momentum) {
+		return raw / sgdDenominator(learnRate)
+	}
+	momentum[idx] = (beta1Q*momentum[idx] + (fixedPointScale-beta1Q)*raw) / fixedPointScale
+	return momentum[idx] / sgdDenominator(learnRate)
+}
+
+// AdamOptimizer 同时维护一阶矩(动量)与二阶矩(梯度平方)，用二阶矩的平方根归一化步长，
+// 对学习速度不同的分桶自适应收敛，不另做偏差修正以保持整数运算简单。
+type AdamOptimizer struct{}
+
+func (AdamOptimizer) Step(idx, raw, learnRate int, momentum, variance []int) int {
+	if idx < 0 || idx >= len(momentum) || idx >= len(variance) {
+		return raw / sgdDenominator(learnRate)
+	}
+	momentum[idx] = (beta1Q*momentum[idx] + (fixedPointScale-beta1Q)*raw) / fixedPointScale
+	variance[idx] = (beta2Q*variance[idx] + (fixedPointScale-beta2Q)*raw*raw) / fixedPointScale
+
+	denom := isqrt(variance[idx]*fixedPointScale) + epsQ
+	if denom == 0 {
+		return 0
+	}
+	return (momentum[idx] * fixedPointScale) / denom
+}
+
+// sgdDenominator 学习速度越高分母越小、单步幅度越大，与既有 rateDenominator 的设计思路一致
+func sgdDenominator(learnRate int) int {
+	return max(6, 16-learnRate)
+}
+
+// isqrt 非负整数平方根的整数牛顿迭代，避免在 Q10 定点路径里引入浮点 math.Sqrt
+func isqrt(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	x := n
+	y := (x + 1) / 2
+	for y < x {
+		x = y
+		y = (x + n/x) / 2
+	}
+	return x
+}
+
+// offsetOptimizerFor 按配置选择的优化器返回对应实现；越界时回退到默认的 AdaDelta
+func offsetOptimizerFor(mode int) OffsetOptimizer {
+	switch mode {
+	case optimizerAdaDelta:
+		return nil
+	case optimizerSGD:
+		return SGDOptimizer{}
+	case optimizerMomentum:
+		return MomentumOptimizer{}
+	case optimizerAdam:
+		return AdamOptimizer{}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,102 @@
+package smartcontrol
+
+import (
+	"math"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// GPSmoothOffsets 使用零均值高斯过程(RBF 核)对学习偏移做后验均值平滑。
+// lengthScale(ℓ) 控制温度上相邻曲线点共享学习信息的程度，noise(σ) 表示观测噪声。
+// 曲线点数量通常只有几十个，直接用 Cholesky 分解求解即可。
+func GPSmoothOffsets(offsets []int, curve []types.FanCurvePoint, lengthScale, noise float64) []int {
+	n := len(offsets)
+	if n == 0 || len(curve) != n {
+		return offsets
+	}
+	if lengthScale <= 0 {
+		lengthScale = 1
+	}
+	if noise < 0 {
+		noise = 0
+	}
+
+	k := make([][]float64, n)
+	for i := range k {
+		k[i] = make([]float64, n)
+		for j := range k[i] {
+			dt := float64(curve[i].Temperature - curve[j].Temperature)
+			k[i][j] = math.Exp(-(dt * dt) / (2 * lengthScale * lengthScale))
+		}
+		k[i][i] += noise * noise
+	}
+
+	y := make([]float64, n)
+	for i, offset := range offsets {
+		y[i] = float64(offset)
+	}
+
+	alpha, ok := choleskySolve(k, y)
+	if !ok {
+		return offsets
+	}
+
+	smoothed := make([]int, n)
+	for i := range n {
+		var sum float64
+		for j := range n {
+			sum += k[i][j] * alpha[j]
+		}
+		smoothed[i] = int(math.Round(sum))
+	}
+
+	return smoothed
+}
+
+// choleskySolve 用 Cholesky 分解求解对称正定方程组 a·x = b，a 在过程中不会被修改。
+func choleskySolve(a [][]float64, b []float64) ([]float64, bool) {
+	n := len(a)
+	l := make([][]float64, n)
+	for i := range l {
+		l[i] = make([]float64, n)
+	}
+
+	for i := range n {
+		for j := 0; j <= i; j++ {
+			sum := a[i][j]
+			for kk := 0; kk < j; kk++ {
+				sum -= l[i][kk] * l[j][kk]
+			}
+			if i == j {
+				if sum <= 0 {
+					return nil, false
+				}
+				l[i][j] = math.Sqrt(sum)
+			} else {
+				l[i][j] = sum / l[j][j]
+			}
+		}
+	}
+
+	// 前代求解 L·z = b
+	z := make([]float64, n)
+	for i := range n {
+		sum := b[i]
+		for j := 0; j < i; j++ {
+			sum -= l[i][j] * z[j]
+		}
+		z[i] = sum / l[i][i]
+	}
+
+	// 回代求解 Lᵀ·x = z
+	x := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		sum := z[i]
+		for j := i + 1; j < n; j++ {
+			sum -= l[j][i] * x[j]
+		}
+		x[i] = sum / l[i][i]
+	}
+
+	return x, true
+}
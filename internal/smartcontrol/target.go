@@ -5,8 +5,63 @@ import (
 	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
 )
 
-// CalculateTargetRPM 计算智能目标转速
-func CalculateTargetRPM(avgTemp, lastAvgTemp int, curve []types.FanCurvePoint, cfg types.SmartControlConfig) int {
+// maxCandidateScore 评分调度器各子分的满分，权重字段直接乘在子分上作为线性组合系数
+const maxCandidateScore = 100
+
+// scoredCandidateSteps 相对曲线基准转速的评分候选偏移网格(RPM)，RampUp/RampDownLimit 另外追加
+var scoredCandidateSteps = []int{-400, -200, -100, -50, 0, 50, 100, 200, 400}
+
+// thermalSensitivityRPMPerDegree 评分调度器里转速变化对预测温度的简化折算系数，
+// 每偏离基准转速这么多 RPM 大致对应 1°C 的预测温度变化
+const thermalSensitivityRPMPerDegree = 50
+
+// CalculateTargetRPM 计算智能目标转速。cfg.PredictiveMode=1 时在 heuristicTargetRPM 之上
+// 在线更新 ThermalModel 并走 PredictRPM 做 MPC 前瞻预测，返回更新后的 ThermalModel 供调用方
+// 持久化；否则直接返回 heuristicTargetRPM 的结果，ThermalModel 原样透传、updated=false。
+func CalculateTargetRPM(avgTemp, lastAvgTemp, ambient int, curve []types.FanCurvePoint, cfg types.SmartControlConfig) (int, types.ThermalModel, bool) {
+	targetRPM := heuristicTargetRPM(avgTemp, lastAvgTemp, curve, cfg)
+	if targetRPM <= 0 || cfg.PredictiveMode != 1 {
+		return targetRPM, cfg.ThermalModel, false
+	}
+
+	model, updated := UpdateThermalModel(avgTemp, lastAvgTemp, ambient, targetRPM, curve, cfg, cfg.ThermalModel)
+	predicted := PredictRPM(avgTemp, lastAvgTemp, ambient, curve, cfg, model, cfg.PredictionHorizon)
+	return clampInt(predicted, 0, 4000), model, updated
+}
+
+// heuristicTargetRPM 计算现有启发式目标转速；cfg.Scheduler="legacy" 时走旧版加性启发式叠加，
+// 否则走多因子评分候选选择器。PredictRPM 的回退路径也复用这里，避免与 CalculateTargetRPM 的
+// MPC 分支相互递归。
+func heuristicTargetRPM(avgTemp, lastAvgTemp int, curve []types.FanCurvePoint, cfg types.SmartControlConfig) int {
+	baselineRPM := curveBaselineRPM(avgTemp, lastAvgTemp, curve, cfg)
+	if baselineRPM <= 0 {
+		return 0
+	}
+
+	tempDelta := avgTemp - lastAvgTemp
+	var targetRPM int
+	if cfg.Scheduler == "legacy" {
+		targetRPM = legacyAdditiveRPM(avgTemp, lastAvgTemp, baselineRPM, cfg)
+	} else {
+		targetRPM = scoredCandidateRPM(avgTemp, tempDelta, baselineRPM, curve, cfg)
+	}
+
+	if cfg.LearnMode == 1 {
+		tree := NewRegressionTree(cfg.RegressionTreeNodes, cfg.RegressionTreeReservoirs)
+		features := TreeFeatures{
+			AvgTemp:        avgTemp,
+			TempDelta:      tempDelta,
+			LastTargetRPM:  targetRPM,
+			OverheatMargin: max(0, avgTemp-(cfg.TargetTemp+cfg.Hysteresis)),
+		}
+		targetRPM += tree.Predict(features)
+	}
+
+	return clampInt(targetRPM, 0, 4000)
+}
+
+// curveBaselineRPM 把学习偏移叠加到曲线上后，按当前温度插值出的基准转速
+func curveBaselineRPM(avgTemp, lastAvgTemp int, curve []types.FanCurvePoint, cfg types.SmartControlConfig) int {
 	effectiveCurve := make([]types.FanCurvePoint, len(curve))
 	activeOffsets := selectOffsetsForTrend(avgTemp-lastAvgTemp, cfg)
 	leftMinRPM, rightMaxRPM := getCurveEdgeRPMBounds(curve)
@@ -25,10 +80,13 @@ func CalculateTargetRPM(avgTemp, lastAvgTemp int, curve []types.FanCurvePoint, c
 	}
 	enforceNonDecreasingRPM(effectiveCurve)
 
-	targetRPM := temperature.CalculateTargetRPM(avgTemp, effectiveCurve)
-	if targetRPM <= 0 {
-		return 0
-	}
+	return temperature.CalculateTargetRPM(avgTemp, effectiveCurve)
+}
+
+// legacyAdditiveRPM 旧版加性启发式叠加：过热惩罚、变化率偏置、温升前馈与预热助推依次累加，
+// 由 cfg.Scheduler="legacy" 保留以便与评分调度器 A/B 对比
+func legacyAdditiveRPM(avgTemp, lastAvgTemp, baselineRPM int, cfg types.SmartControlConfig) int {
+	targetRPM := baselineRPM
 
 	tempError := avgTemp - cfg.TargetTemp
 	if absInt(tempError) > cfg.Hysteresis {
@@ -56,7 +114,64 @@ func CalculateTargetRPM(avgTemp, lastAvgTemp int, curve []types.FanCurvePoint, c
 		targetRPM += 320 + cfg.OverheatWeight*15
 	}
 
-	return clampInt(targetRPM, 0, 4000)
+	return targetRPM
+}
+
+// scoredCandidateRPM 在基准转速附近生成候选网格，按 thermalMargin/noisePenalty/wearPenalty/
+// trendResponsiveness 四个独立归一化子分，用既有权重字段线性组合后取argmax
+func scoredCandidateRPM(avgTemp, tempDelta, baselineRPM int, curve []types.FanCurvePoint, cfg types.SmartControlConfig) int {
+	leftMinRPM, rightMaxRPM := getCurveEdgeRPMBounds(curve)
+	deltas := append(append([]int(nil), scoredCandidateSteps...), -cfg.RampDownLimit, cfg.RampUpLimit)
+	candidates := candidateRPMsFromDeltas(baselineRPM, deltas, leftMinRPM, rightMaxRPM)
+
+	bestRPM := baselineRPM
+	bestScore := -1
+	for _, rpm := range candidates {
+		predictedTemp := avgTemp + tempDelta - (rpm-baselineRPM)/thermalSensitivityRPMPerDegree
+
+		score := scoreThermalMargin(predictedTemp, cfg.TargetTemp)*cfg.OverheatWeight +
+			scoreNoisePenalty(rpm, cfg.QuietRPMThreshold)*cfg.NoiseWeight +
+			scoreWearPenalty(rpm, baselineRPM)*cfg.RPMDeltaWeight +
+			scoreTrendResponsiveness(rpm, baselineRPM, tempDelta)*cfg.TrendGain
+
+		if score > bestScore {
+			bestScore = score
+			bestRPM = rpm
+		}
+	}
+
+	return bestRPM
+}
+
+// scoreThermalMargin 预测终温越接近目标温度分越高，每偏离1°C扣6分
+func scoreThermalMargin(predictedTemp, targetTemp int) int {
+	return clampInt(maxCandidateScore-absInt(predictedTemp-targetTemp)*6, 0, maxCandidateScore)
+}
+
+// scoreNoisePenalty 转速不超过安静阈值时满分，超出部分每8RPM扣1分
+func scoreNoisePenalty(rpm, quietThreshold int) int {
+	if rpm <= quietThreshold {
+		return maxCandidateScore
+	}
+	return clampInt(maxCandidateScore-(rpm-quietThreshold)/8, 0, maxCandidateScore)
+}
+
+// scoreWearPenalty 候选转速偏离曲线基准越多分越低，每5RPM扣1分
+func scoreWearPenalty(rpm, baselineRPM int) int {
+	return clampInt(maxCandidateScore-absInt(rpm-baselineRPM)/5, 0, maxCandidateScore)
+}
+
+// scoreTrendResponsiveness 候选转速的变化方向与当前温升/温降趋势一致时给满分，相反则大幅扣分，
+// 无趋势时给中性分
+func scoreTrendResponsiveness(rpm, baselineRPM, tempDelta int) int {
+	if tempDelta == 0 || rpm == baselineRPM {
+		return maxCandidateScore / 2
+	}
+	movesWithTrend := (rpm > baselineRPM) == (tempDelta > 0)
+	if movesWithTrend {
+		return maxCandidateScore
+	}
+	return maxCandidateScore / 4
 }
 
 func selectOffsetsForTrend(tempDelta int, cfg types.SmartControlConfig) []int {
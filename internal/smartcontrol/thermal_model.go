@@ -0,0 +1,148 @@
+package smartcontrol
+
+import (
+	"math"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// minThermalSamples 分桶至少积累这么多次在线拟合样本后，PredictRPM 才信任该桶的模型参数，
+// 否则回退到启发式的 heuristicTargetRPM。
+const minThermalSamples = 8
+
+// candidateRPMSteps 相对曲线基准转速的候选偏移网格(RPM)
+var candidateRPMSteps = []int{-400, -200, -100, -50, 0, 50, 100, 200, 400}
+
+// UpdateThermalModel 按曲线点分桶在线拟合一阶热模型：用 EMA 最小二乘分别回归出
+// KCool(转速驱动的冷却增益)与 Tau(热惯性时间常数)，解耦于 learning.go 的偏移学习。
+func UpdateThermalModel(avgTemp, lastAvgTemp, ambient, currentRPM int, curve []types.FanCurvePoint, cfg types.SmartControlConfig, model types.ThermalModel) (types.ThermalModel, bool) {
+	if len(curve) == 0 {
+		return model, false
+	}
+	if resized, updated := resizeThermalModel(model, curve); updated {
+		model = resized
+	}
+
+	idx := nearestCurveIndex(avgTemp, curve)
+	if idx < 0 || idx >= len(model.KCool) {
+		return model, false
+	}
+
+	learnWindow := max(3, cfg.LearnWindow)
+	decay := float64(learnWindow) / float64(learnWindow+1)
+
+	tempDelta := avgTemp - lastAvgTemp
+	rpmX := float64(currentRPM) / 1000
+	rpmY := float64(-tempDelta) // 转速越高、温度下降越多，cooling 为正
+
+	model.SxxRPM[idx] = decay*model.SxxRPM[idx] + rpmX*rpmX
+	model.SxyRPM[idx] = decay*model.SxyRPM[idx] + rpmX*rpmY
+	if model.SxxRPM[idx] > 1e-6 {
+		model.KCool[idx] = clampFloat(model.SxyRPM[idx]/model.SxxRPM[idx], 0, 2)
+	}
+
+	// residual 剔除已知的转速冷却项后，剩余的温差变化全部归因于热惯性弛豫
+	tempX := float64(avgTemp - ambient)
+	residual := float64(tempDelta) + model.KCool[idx]*rpmX
+	model.SxxTemp[idx] = decay*model.SxxTemp[idx] + tempX*tempX
+	model.SxyTemp[idx] = decay*model.SxyTemp[idx] + tempX*(-residual)
+	if model.SxxTemp[idx] > 1e-6 {
+		if invTau := model.SxyTemp[idx] / model.SxxTemp[idx]; invTau > 1e-4 {
+			model.Tau[idx] = clampFloat(1/invTau, 5, 600)
+		}
+	}
+
+	model.Samples[idx]++
+	return model, true
+}
+
+// PredictRPM 对曲线基准转速附近的候选网格做短时域前瞻模拟，选择预测终温代价最小的转速；
+// 命中分桶样本不足时回退到启发式的 heuristicTargetRPM。
+func PredictRPM(avgTemp, lastAvgTemp, ambient int, curve []types.FanCurvePoint, cfg types.SmartControlConfig, model types.ThermalModel, horizonSeconds int) int {
+	fallback := heuristicTargetRPM(avgTemp, lastAvgTemp, curve, cfg)
+	if len(curve) == 0 {
+		return fallback
+	}
+
+	idx := nearestCurveIndex(avgTemp, curve)
+	if idx < 0 || idx >= len(model.Samples) || model.Samples[idx] < minThermalSamples {
+		return fallback
+	}
+	kCool := model.KCool[idx]
+	tau := model.Tau[idx]
+	if tau <= 0 {
+		return fallback
+	}
+
+	leftMinRPM, rightMaxRPM := getCurveEdgeRPMBounds(curve)
+	bestRPM := fallback
+	bestCost := math.MaxFloat64
+	for _, rpm := range candidateRPMsFromDeltas(fallback, candidateRPMSteps, leftMinRPM, rightMaxRPM) {
+		predictedTemp := simulateTemp(float64(avgTemp), float64(ambient), kCool, tau, rpm, max(1, horizonSeconds))
+		cost := predictionCost(predictedTemp, rpm, fallback, cfg)
+		if cost < bestCost {
+			bestCost = cost
+			bestRPM = rpm
+		}
+	}
+
+	return clampInt(bestRPM, 0, 4000)
+}
+
+// simulateTemp 以 1 秒为步长向前欧拉积分 T(t+1) = T + (1/tau) * (equilibrium - T)，
+// equilibrium 是该转速下 dT/dt=0 时的稳态温度：ambient - KCool*Tau*(rpm/1000)。
+func simulateTemp(temp, ambient, kCool, tau float64, rpm, horizonSeconds int) float64 {
+	equilibrium := ambient - kCool*tau*(float64(rpm)/1000)
+	for range horizonSeconds {
+		temp += (equilibrium - temp) / tau
+	}
+	return temp
+}
+
+// predictionCost 复用现有的权重字段(OverheatWeight/RPMDeltaWeight/NoiseWeight)把终温误差、
+// 转速变化量、噪音水平折算为同一把代价函数
+func predictionCost(predictedTemp float64, rpm, baseRPM int, cfg types.SmartControlConfig) float64 {
+	tempCost := math.Abs(predictedTemp-float64(cfg.TargetTemp)) * float64(cfg.OverheatWeight)
+	rpmChangeCost := float64(absInt(rpm-baseRPM)) * float64(cfg.RPMDeltaWeight) / 10
+	noiseCost := float64(rpm) * float64(cfg.NoiseWeight) / 1000
+	return tempCost + rpmChangeCost + noiseCost
+}
+
+// resizeThermalModel 将模型各分桶切片对齐到 curve 长度，新增分桶沿用出厂经验初值
+func resizeThermalModel(model types.ThermalModel, curve []types.FanCurvePoint) (types.ThermalModel, bool) {
+	size := len(curve)
+	if len(model.KCool) == size && len(model.Tau) == size && len(model.SxxRPM) == size &&
+		len(model.SxyRPM) == size && len(model.SxxTemp) == size && len(model.SxyTemp) == size &&
+		len(model.Samples) == size {
+		return model, false
+	}
+
+	defaults := types.GetDefaultThermalModel(curve)
+	resized := types.ThermalModel{
+		KCool:   append([]float64(nil), defaults.KCool...),
+		Tau:     append([]float64(nil), defaults.Tau...),
+		SxxRPM:  make([]float64, size),
+		SxyRPM:  make([]float64, size),
+		SxxTemp: make([]float64, size),
+		SxyTemp: make([]float64, size),
+		Samples: make([]int, size),
+	}
+	copy(resized.KCool, model.KCool)
+	copy(resized.Tau, model.Tau)
+	copy(resized.SxxRPM, model.SxxRPM)
+	copy(resized.SxyRPM, model.SxyRPM)
+	copy(resized.SxxTemp, model.SxxTemp)
+	copy(resized.SxyTemp, model.SxyTemp)
+	copy(resized.Samples, model.Samples)
+	return resized, true
+}
+
+func clampFloat(value, minValue, maxValue float64) float64 {
+	if value < minValue {
+		return minValue
+	}
+	if value > maxValue {
+		return maxValue
+	}
+	return value
+}
@@ -0,0 +1,267 @@
+package smartcontrol
+
+import "github.com/TIANLI0/BS2PRO-Controller/internal/types"
+
+const (
+	treeMaxDepth          = 4
+	treeMaxLeafSamples    = 64
+	treeMinSamplesToSplit = 12
+)
+
+// TreeFeatures 回归树学习模式使用的特征向量
+type TreeFeatures struct {
+	AvgTemp        int
+	TempDelta      int
+	LastTargetRPM  int
+	OverheatMargin int
+}
+
+func (f TreeFeatures) at(feature int) int {
+	switch feature {
+	case 0:
+		return f.AvgTemp
+	case 1:
+		return f.TempDelta
+	case 2:
+		return f.LastTargetRPM
+	default:
+		return f.OverheatMargin
+	}
+}
+
+type treeSample struct {
+	features TreeFeatures
+	target   int
+}
+
+// RegressionTree 固定深度上限、按方差下降在线分裂的小型 CART 回归树。
+// 叶子的样本蓄水池用于在线分裂决策，经 Reservoirs 与节点一起持久化，由 NewRegressionTree 恢复后继续预测与生长。
+type RegressionTree struct {
+	nodes      []types.RegressionTreeNode
+	reservoirs [][]treeSample
+}
+
+// NewRegressionTree 从已持久化的节点数组与蓄水池样本恢复回归树；传入空节点切片则创建仅含根叶子的新树。
+// reservoirs 按下标对应 nodes，长度不匹配(如节点数组是旧格式、尚无蓄水池数据)时按空蓄水池处理。
+func NewRegressionTree(nodes []types.RegressionTreeNode, reservoirs [][]types.RegressionTreeSample) *RegressionTree {
+	t := &RegressionTree{}
+	if len(nodes) == 0 {
+		t.nodes = []types.RegressionTreeNode{{Leaf: true, Left: -1, Right: -1}}
+	} else {
+		t.nodes = append([]types.RegressionTreeNode(nil), nodes...)
+	}
+
+	t.reservoirs = make([][]treeSample, len(t.nodes))
+	for i := range t.reservoirs {
+		if i >= len(reservoirs) {
+			break
+		}
+		t.reservoirs[i] = fromPersistedSamples(reservoirs[i])
+	}
+	return t
+}
+
+// Nodes 返回可持久化的节点数组快照
+func (t *RegressionTree) Nodes() []types.RegressionTreeNode {
+	return append([]types.RegressionTreeNode(nil), t.nodes...)
+}
+
+// Reservoirs 返回可持久化的叶子蓄水池快照，与 Nodes 按下标一一对应
+func (t *RegressionTree) Reservoirs() [][]types.RegressionTreeSample {
+	reservoirs := make([][]types.RegressionTreeSample, len(t.reservoirs))
+	for i, reservoir := range t.reservoirs {
+		reservoirs[i] = toPersistedSamples(reservoir)
+	}
+	return reservoirs
+}
+
+func toPersistedSamples(samples []treeSample) []types.RegressionTreeSample {
+	if len(samples) == 0 {
+		return nil
+	}
+	persisted := make([]types.RegressionTreeSample, len(samples))
+	for i, s := range samples {
+		persisted[i] = types.RegressionTreeSample{
+			AvgTemp:        s.features.AvgTemp,
+			TempDelta:      s.features.TempDelta,
+			LastTargetRPM:  s.features.LastTargetRPM,
+			OverheatMargin: s.features.OverheatMargin,
+			Target:         s.target,
+		}
+	}
+	return persisted
+}
+
+func fromPersistedSamples(samples []types.RegressionTreeSample) []treeSample {
+	if len(samples) == 0 {
+		return nil
+	}
+	restored := make([]treeSample, len(samples))
+	for i, s := range samples {
+		restored[i] = treeSample{
+			features: TreeFeatures{
+				AvgTemp:        s.AvgTemp,
+				TempDelta:      s.TempDelta,
+				LastTargetRPM:  s.LastTargetRPM,
+				OverheatMargin: s.OverheatMargin,
+			},
+			target: s.Target,
+		}
+	}
+	return restored
+}
+
+// Predict 沿树走到叶子，返回该叶子当前的预测转速修正量(RPM)
+func (t *RegressionTree) Predict(features TreeFeatures) int {
+	return t.nodes[t.leafIndex(features)].Value
+}
+
+// Update 将一个观测样本写入对应叶子的蓄水池，必要时按方差下降对该叶子做一次分裂
+func (t *RegressionTree) Update(features TreeFeatures, targetDelta int) {
+	idx := t.leafIndex(features)
+
+	reservoir := append(t.reservoirs[idx], treeSample{features: features, target: targetDelta})
+	if len(reservoir) > treeMaxLeafSamples {
+		reservoir = reservoir[len(reservoir)-treeMaxLeafSamples:]
+	}
+	t.reservoirs[idx] = reservoir
+	t.nodes[idx].Value = meanTarget(reservoir)
+
+	if len(reservoir) >= treeMinSamplesToSplit && t.depthOf(idx) < treeMaxDepth {
+		t.trySplit(idx)
+	}
+}
+
+func (t *RegressionTree) leafIndex(features TreeFeatures) int {
+	idx := 0
+	for !t.nodes[idx].Leaf {
+		node := t.nodes[idx]
+		if features.at(node.FeatureIndex) <= node.Threshold {
+			idx = node.Left
+		} else {
+			idx = node.Right
+		}
+	}
+	return idx
+}
+
+func (t *RegressionTree) depthOf(idx int) int {
+	depth := make([]int, len(t.nodes))
+	var walk func(i, d int)
+	walk = func(i, d int) {
+		depth[i] = d
+		if node := t.nodes[i]; !node.Leaf {
+			walk(node.Left, d+1)
+			walk(node.Right, d+1)
+		}
+	}
+	walk(0, 0)
+	return depth[idx]
+}
+
+// trySplit 在叶子 idx 的蓄水池上，为每个特征枚举候选阈值，选择方差下降最大的切分
+func (t *RegressionTree) trySplit(idx int) {
+	samples := t.reservoirs[idx]
+	baseVariance := variance(samples)
+
+	bestFeature, bestThreshold, bestGain := -1, 0, 0.0
+	for feature := range 4 {
+		for _, threshold := range candidateThresholds(samples, feature) {
+			left, right := splitSamples(samples, feature, threshold)
+			if len(left) == 0 || len(right) == 0 {
+				continue
+			}
+			weighted := (float64(len(left))*variance(left) + float64(len(right))*variance(right)) / float64(len(samples))
+			if gain := baseVariance - weighted; gain > bestGain {
+				bestGain, bestFeature, bestThreshold = gain, feature, threshold
+			}
+		}
+	}
+
+	if bestFeature < 0 || bestGain <= 0 {
+		return
+	}
+
+	left, right := splitSamples(samples, bestFeature, bestThreshold)
+	leftIdx := len(t.nodes)
+	rightIdx := leftIdx + 1
+	t.nodes = append(t.nodes,
+		types.RegressionTreeNode{Leaf: true, Left: -1, Right: -1, Value: meanTarget(left)},
+		types.RegressionTreeNode{Leaf: true, Left: -1, Right: -1, Value: meanTarget(right)},
+	)
+	t.reservoirs = append(t.reservoirs, left, right)
+
+	t.nodes[idx] = types.RegressionTreeNode{
+		FeatureIndex: bestFeature,
+		Threshold:    bestThreshold,
+		Left:         leftIdx,
+		Right:        rightIdx,
+	}
+	t.reservoirs[idx] = nil
+}
+
+func splitSamples(samples []treeSample, feature, threshold int) (left, right []treeSample) {
+	for _, s := range samples {
+		if s.features.at(feature) <= threshold {
+			left = append(left, s)
+		} else {
+			right = append(right, s)
+		}
+	}
+	return left, right
+}
+
+func candidateThresholds(samples []treeSample, feature int) []int {
+	seen := make(map[int]bool, len(samples))
+	thresholds := make([]int, 0, len(samples))
+	for _, s := range samples {
+		v := s.features.at(feature)
+		if !seen[v] {
+			seen[v] = true
+			thresholds = append(thresholds, v)
+		}
+	}
+	return thresholds
+}
+
+func meanTarget(samples []treeSample) int {
+	if len(samples) == 0 {
+		return 0
+	}
+	sum := 0
+	for _, s := range samples {
+		sum += s.target
+	}
+	return sum / len(samples)
+}
+
+func variance(samples []treeSample) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	mean := float64(meanTarget(samples))
+	var sum float64
+	for _, s := range samples {
+		diff := float64(s.target) - mean
+		sum += diff * diff
+	}
+	return sum / float64(len(samples))
+}
+
+// LearnRegressionTree 在回归树学习模式下，用本次观测到的转速修正量更新树结构，返回需要
+// 和其余学习状态一起持久化的节点数组与叶子蓄水池样本。蓄水池必须随节点一起持久化并在下次
+// 调用时传回 cfg，否则每次调用都从空蓄水池重新起步，永远攒不够 treeMinSamplesToSplit 个
+// 样本去分裂。
+func LearnRegressionTree(avgTemp, lastAvgTemp, targetRPM, lastTargetRPM int, cfg types.SmartControlConfig) ([]types.RegressionTreeNode, [][]types.RegressionTreeSample) {
+	tree := NewRegressionTree(cfg.RegressionTreeNodes, cfg.RegressionTreeReservoirs)
+
+	features := TreeFeatures{
+		AvgTemp:        avgTemp,
+		TempDelta:      avgTemp - lastAvgTemp,
+		LastTargetRPM:  lastTargetRPM,
+		OverheatMargin: max(0, avgTemp-(cfg.TargetTemp+cfg.Hysteresis)),
+	}
+	tree.Update(features, targetRPM-lastTargetRPM)
+
+	return tree.Nodes(), tree.Reservoirs()
+}
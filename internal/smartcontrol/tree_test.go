@@ -0,0 +1,82 @@
+package smartcontrol
+
+import (
+	"testing"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// xorTarget 构造一个只有同时知道 avgTemp 与 tempDelta 的符号才能预测的交互：
+// 高温+升温 或 低温+降温 时需要大幅提速，反之(高温+降温、低温+升温)则不需要。
+// 单一特征(仅 avgTemp 或仅 tempDelta)与目标值的边际相关性为零，分桶曲线偏移无法表达这种交互。
+func xorTarget(avgTemp, tempDelta int) int {
+	highTemp := avgTemp > 55
+	rising := tempDelta > 0
+	if highTemp == rising {
+		return 60
+	}
+	return -60
+}
+
+// TestRegressionTreeLearnsXORLikeInteraction 验证回归树能学到 avgTemp 与 tempDelta 的交互，
+// 而不是退化成一个与两者边际相关性都为零的全局均值。
+func TestRegressionTreeLearnsXORLikeInteraction(t *testing.T) {
+	tree := NewRegressionTree(nil, nil)
+
+	// 第一阶段：tempDelta 固定为 0，让根节点先按 avgTemp 分裂(此时与 avgTemp 的边际相关性非零)。
+	for range 20 {
+		tree.Update(TreeFeatures{AvgTemp: 30, TempDelta: 0}, xorTarget(30, -1))
+		tree.Update(TreeFeatures{AvgTemp: 80, TempDelta: 0}, xorTarget(80, -1))
+	}
+
+	// 第二阶段：在每个 avgTemp 分支内引入 tempDelta 的真实交互，促使该分支再按 tempDelta 分裂。
+	for range 30 {
+		tree.Update(TreeFeatures{AvgTemp: 30, TempDelta: -5}, xorTarget(30, -5))
+		tree.Update(TreeFeatures{AvgTemp: 30, TempDelta: 5}, xorTarget(30, 5))
+		tree.Update(TreeFeatures{AvgTemp: 80, TempDelta: -5}, xorTarget(80, -5))
+		tree.Update(TreeFeatures{AvgTemp: 80, TempDelta: 5}, xorTarget(80, 5))
+	}
+
+	cases := []struct {
+		avgTemp, tempDelta int
+	}{
+		{30, -5},
+		{30, 5},
+		{80, -5},
+		{80, 5},
+	}
+	for _, c := range cases {
+		want := xorTarget(c.avgTemp, c.tempDelta)
+		got := tree.Predict(TreeFeatures{AvgTemp: c.avgTemp, TempDelta: c.tempDelta})
+		if signInt(got) != signInt(want) {
+			t.Errorf("Predict(avgTemp=%d, tempDelta=%d) = %d, want same sign as %d", c.avgTemp, c.tempDelta, got, want)
+		}
+	}
+
+	// 一个不知道交互、只能输出全局均值的预测器必然在至少一半组合上符号错误；
+	// 确认树至少分裂出了不止一个叶子，证明它没有退化成那个均值预测器。
+	if len(tree.Nodes()) <= 1 {
+		t.Fatalf("tree did not split at all, got %d node(s)", len(tree.Nodes()))
+	}
+}
+
+// TestLearnRegressionTreePersistsReservoirsAcrossCalls 验证 LearnRegressionTree 的蓄水池样本
+// 能跨调用持久化：调用方把返回的 RegressionTreeReservoirs 和 RegressionTreeNodes 一起写回 cfg
+// 后再次调用时，样本应当继续累积而不是每次都从空蓄水池重新起步(否则永远攒不够样本分裂)。
+func TestLearnRegressionTreePersistsReservoirsAcrossCalls(t *testing.T) {
+	cfg := types.SmartControlConfig{TargetTemp: 60, Hysteresis: 2}
+
+	for i := range 20 {
+		avgTemp, targetRPM := 80, -50
+		if i%2 == 0 {
+			avgTemp, targetRPM = 30, 50
+		}
+		nodes, reservoirs := LearnRegressionTree(avgTemp, avgTemp, targetRPM, 0, cfg)
+		cfg.RegressionTreeNodes = nodes
+		cfg.RegressionTreeReservoirs = reservoirs
+	}
+
+	if len(cfg.RegressionTreeNodes) <= 1 {
+		t.Fatalf("tree never split after 20 calls, got %d node(s); reservoir samples are not persisting across calls", len(cfg.RegressionTreeNodes))
+	}
+}
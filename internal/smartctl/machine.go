@@ -0,0 +1,121 @@
+package smartctl
+
+import (
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// Machine 驱动智能控温状态转换，并为每个状态应用各自的 RPM 策略
+type Machine struct {
+	state      State
+	dwellCount int
+	events     chan StateChange
+	stopped    bool
+}
+
+// NewMachine 创建状态机，initialState 通常来自 SmartControlConfig.LastState 以便重启后恢复
+func NewMachine(initialState State) *Machine {
+	return &Machine{
+		state:  initialState,
+		events: make(chan StateChange, 8),
+	}
+}
+
+// State 返回当前状态
+func (m *Machine) State() State {
+	return m.state
+}
+
+// Events 返回只读的状态变化事件通道，供托盘/GUI 订阅展示当前阶段
+func (m *Machine) Events() <-chan StateChange {
+	return m.events
+}
+
+// Stop 显式停止智能控温，进入 Stopped 状态
+func (m *Machine) Stop() {
+	m.transition(Stopped)
+	m.stopped = true
+}
+
+// Resume 从 Stopped 恢复到 Idle，下一次 Step 会重新评估
+func (m *Machine) Resume() {
+	m.stopped = false
+	m.transition(Idle)
+}
+
+// Step 根据一次温度采样推进状态机，返回新状态下应当采用的 RPM 策略
+func (m *Machine) Step(maxTemp int, cfg types.SmartControlConfig) Policy {
+	if m.stopped {
+		return policyFor(Stopped, cfg)
+	}
+
+	upper := cfg.TargetTemp + cfg.Hysteresis
+	lower := cfg.TargetTemp - cfg.Hysteresis
+	overheat := cfg.TargetTemp + cfg.OverheatDelta
+
+	if maxTemp >= overheat {
+		m.transition(Overheat)
+		m.dwellCount = 0
+		return policyFor(Overheat, cfg)
+	}
+
+	switch m.state {
+	case Idle, CoolDown, Overheat, Stopped:
+		if maxTemp > upper {
+			m.transition(RampUp)
+			m.dwellCount = 0
+		} else if maxTemp >= lower && maxTemp <= upper {
+			m.advanceDwell(cfg.SteadyStateDwell, Holding)
+		}
+
+	case RampUp:
+		if maxTemp >= lower && maxTemp <= upper {
+			m.advanceDwell(cfg.SteadyStateDwell, Holding)
+		} else if maxTemp <= upper {
+			m.dwellCount = 0
+		}
+
+	case Holding:
+		if maxTemp > upper {
+			m.transition(RampUp)
+			m.dwellCount = 0
+		} else if maxTemp < lower {
+			m.advanceDwell(cfg.SteadyStateDwell, CoolDown)
+		} else {
+			m.dwellCount = 0
+		}
+	}
+
+	return policyFor(m.state, cfg)
+}
+
+// advanceDwell 在候选状态内连续停留达到 dwellTicks 次采样后才真正切换状态，避免抖动
+func (m *Machine) advanceDwell(dwellTicks int, candidate State) {
+	if m.state == candidate {
+		m.dwellCount = 0
+		return
+	}
+	m.dwellCount++
+	if m.dwellCount >= dwellTicks {
+		m.transition(candidate)
+		m.dwellCount = 0
+	}
+}
+
+func (m *Machine) transition(to State) {
+	if m.state == to {
+		return
+	}
+	from := m.state
+	m.state = to
+
+	select {
+	case m.events <- StateChange{From: from, To: to}:
+	default:
+		// 事件通道已满，订阅方尚未消费旧事件；丢弃最旧的一条以保证最新状态可见
+		select {
+		case <-m.events:
+		default:
+		}
+		m.events <- StateChange{From: from, To: to}
+	}
+}
@@ -0,0 +1,60 @@
+package smartctl
+
+import "github.com/TIANLI0/BS2PRO-Controller/internal/types"
+
+// Policy 描述某个状态下应当使用的升降速限幅、最小生效变化量，以及是否允许学习更新
+type Policy struct {
+	State         State
+	RampUpLimit   int
+	RampDownLimit int
+	MinRPMChange  int
+	ForceMaxRPM   bool
+	AllowLearning bool
+}
+
+// policyFor 按状态返回对应的 RPM 策略；只有 Holding 状态允许学习偏移更新，避免在升降温瞬态中污染模型
+func policyFor(state State, cfg types.SmartControlConfig) Policy {
+	switch state {
+	case Overheat:
+		return Policy{
+			State:         state,
+			RampUpLimit:   cfg.RampUpLimit * 4,
+			RampDownLimit: cfg.RampDownLimit,
+			MinRPMChange:  0,
+			ForceMaxRPM:   true,
+			AllowLearning: false,
+		}
+	case RampUp:
+		return Policy{
+			State:         state,
+			RampUpLimit:   cfg.RampUpLimit,
+			RampDownLimit: cfg.RampDownLimit,
+			MinRPMChange:  cfg.MinRPMChange,
+			AllowLearning: false,
+		}
+	case Holding:
+		return Policy{
+			State:         state,
+			RampUpLimit:   cfg.RampUpLimit,
+			RampDownLimit: cfg.RampDownLimit,
+			MinRPMChange:  cfg.MinRPMChange,
+			AllowLearning: true,
+		}
+	case CoolDown:
+		return Policy{
+			State:         state,
+			RampUpLimit:   cfg.RampUpLimit,
+			RampDownLimit: cfg.RampDownLimit,
+			MinRPMChange:  cfg.MinRPMChange,
+			AllowLearning: false,
+		}
+	default: // Idle, Stopped
+		return Policy{
+			State:         state,
+			RampUpLimit:   cfg.RampUpLimit,
+			RampDownLimit: cfg.RampDownLimit,
+			MinRPMChange:  cfg.MinRPMChange,
+			AllowLearning: false,
+		}
+	}
+}
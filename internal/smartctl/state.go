@@ -0,0 +1,64 @@
+// Package smartctl 将智能控温的控制逻辑建模为显式状态机，取代原先的临时判断分支
+package smartctl
+
+// State 智能控温状态机的阶段
+type State int
+
+const (
+	// Idle 智能控温未开始评估(刚启动或被禁用)
+	Idle State = iota
+	// RampUp 温度高于目标温度+滞回带，正在升速追赶
+	RampUp
+	// Holding 温度已进入滞回带并稳定停留，执行学习与精细调节
+	Holding
+	// Overheat 温度达到过热阈值，强制最大转速
+	Overheat
+	// CoolDown 温度低于目标温度-滞回带，正在降速
+	CoolDown
+	// Stopped 智能控温被显式停止
+	Stopped
+)
+
+// String 返回状态的可读名称，用于日志、托盘提示与持久化
+func (s State) String() string {
+	switch s {
+	case Idle:
+		return "Idle"
+	case RampUp:
+		return "RampUp"
+	case Holding:
+		return "Holding"
+	case Overheat:
+		return "Overheat"
+	case CoolDown:
+		return "CoolDown"
+	case Stopped:
+		return "Stopped"
+	default:
+		return "Unknown"
+	}
+}
+
+// ParseState 将持久化的状态名还原为 State，无法识别时返回 Idle
+func ParseState(name string) State {
+	switch name {
+	case "RampUp":
+		return RampUp
+	case "Holding":
+		return Holding
+	case "Overheat":
+		return Overheat
+	case "CoolDown":
+		return CoolDown
+	case "Stopped":
+		return Stopped
+	default:
+		return Idle
+	}
+}
+
+// StateChange 一次状态迁移事件
+type StateChange struct {
+	From State
+	To   State
+}
@@ -0,0 +1,135 @@
+// Package temperature 管理多个可插拔的温度数据来源，并按用户选择的传感器聚合出 CPU/GPU/存储温度
+package temperature
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// Registry 维护已注册的温度源，并按配置的优先级进行故障转移
+type Registry struct {
+	logger types.Logger
+
+	mu      sync.Mutex
+	sources map[string]types.TemperatureSource
+	active  string
+}
+
+// NewRegistry 创建一个空的温度源注册表
+func NewRegistry(logger types.Logger) *Registry {
+	return &Registry{
+		logger:  logger,
+		sources: make(map[string]types.TemperatureSource),
+	}
+}
+
+// Register 注册一个温度源，重复名称会覆盖旧实例
+func (r *Registry) Register(source types.TemperatureSource) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sources[source.Name()] = source
+}
+
+// ActiveSourceName 返回最近一次成功读取所使用的温度源名称，供托盘提示展示
+func (r *Registry) ActiveSourceName() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.active
+}
+
+// Read 按 cfgs 中启用的温度源优先级依次尝试读取，选取各 category 配置的传感器聚合结果
+func (r *Registry) Read(ctx context.Context, cfgs []types.SourceConfig) (types.TemperatureData, error) {
+	enabled := enabledSourcesByPriority(cfgs)
+	if len(enabled) == 0 {
+		return types.TemperatureData{}, fmt.Errorf("未启用任何温度源")
+	}
+
+	var lastErr error
+	for _, cfg := range enabled {
+		r.mu.Lock()
+		source, ok := r.sources[cfg.Name]
+		r.mu.Unlock()
+		if !ok || !source.Available() {
+			continue
+		}
+
+		reading, err := source.Read(ctx)
+		if err != nil {
+			lastErr = err
+			r.logger.Debug("温度源 %s 读取失败，尝试下一个: %v", cfg.Name, err)
+			continue
+		}
+
+		r.mu.Lock()
+		r.active = cfg.Name
+		r.mu.Unlock()
+
+		return buildTemperatureData(reading, cfg), nil
+	}
+
+	if lastErr != nil {
+		return types.TemperatureData{}, fmt.Errorf("所有已启用温度源均读取失败: %v", lastErr)
+	}
+	return types.TemperatureData{}, fmt.Errorf("没有可用的已启用温度源")
+}
+
+// Sensors 返回指定温度源暴露的可选传感器列表，供配置界面填充下拉框
+func (r *Registry) Sensors(name string) []types.SensorInfo {
+	r.mu.Lock()
+	source, ok := r.sources[name]
+	r.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return source.Sensors()
+}
+
+// buildTemperatureData 按 cfg 中配置的传感器 ID 聚合读数；某个类别未选择传感器(ID为空)时，
+// 该类别留空且不参与 MaxTemp 计算，避免与传感器真实读到 0°C 混淆
+func buildTemperatureData(reading types.Reading, cfg types.SourceConfig) types.TemperatureData {
+	data := types.TemperatureData{BridgeOk: true}
+	var maxCandidates []int
+
+	if cfg.CPUSensor != "" {
+		data.CPUTemp = reading.Values[cfg.CPUSensor]
+		maxCandidates = append(maxCandidates, data.CPUTemp)
+	}
+	if cfg.GPUSensor != "" {
+		data.GPUTemp = reading.Values[cfg.GPUSensor]
+		maxCandidates = append(maxCandidates, data.GPUTemp)
+	}
+	if cfg.StorageSensor != "" {
+		data.StorageTemp = reading.Values[cfg.StorageSensor]
+		maxCandidates = append(maxCandidates, data.StorageTemp)
+	}
+	if len(maxCandidates) > 0 {
+		data.MaxTemp = maxOf(maxCandidates...)
+	}
+
+	return data
+}
+
+func enabledSourcesByPriority(cfgs []types.SourceConfig) []types.SourceConfig {
+	enabled := make([]types.SourceConfig, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		if cfg.Enabled {
+			enabled = append(enabled, cfg)
+		}
+	}
+	sort.Slice(enabled, func(i, j int) bool { return enabled[i].Priority < enabled[j].Priority })
+	return enabled
+}
+
+func maxOf(values ...int) int {
+	max := values[0]
+	for _, v := range values[1:] {
+		if v > max {
+			max = v
+		}
+	}
+	return max
+}
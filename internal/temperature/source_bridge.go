@@ -0,0 +1,52 @@
+package temperature
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// BridgeSource 包装既有的外部桥接程序，作为默认温度源
+type BridgeSource struct {
+	fetch func() (types.BridgeTemperatureData, error)
+}
+
+// NewBridgeSource 创建桥接温度源，fetch 封装与桥接进程的实际通信
+func NewBridgeSource(fetch func() (types.BridgeTemperatureData, error)) *BridgeSource {
+	return &BridgeSource{fetch: fetch}
+}
+
+// Name 返回温度源标识
+func (s *BridgeSource) Name() string { return "bridge" }
+
+// Available 已设置 fetch 时桥接源即视为可尝试，实际可用性以 Read 的返回值为准
+func (s *BridgeSource) Available() bool { return s.fetch != nil }
+
+// Read 调用桥接进程获取一次温度快照
+func (s *BridgeSource) Read(ctx context.Context) (types.Reading, error) {
+	if s.fetch == nil {
+		return types.Reading{}, fmt.Errorf("桥接温度源未初始化")
+	}
+
+	data, err := s.fetch()
+	if err != nil {
+		return types.Reading{}, err
+	}
+	if !data.Success {
+		return types.Reading{}, fmt.Errorf("桥接程序返回失败: %s", data.Error)
+	}
+
+	return types.Reading{Values: map[string]int{
+		"cpu": data.CpuTemp,
+		"gpu": data.GpuTemp,
+	}}, nil
+}
+
+// Sensors 桥接源仅暴露聚合后的 CPU/GPU 两个读数
+func (s *BridgeSource) Sensors() []types.SensorInfo {
+	return []types.SensorInfo{
+		{ID: "cpu", Label: "CPU(桥接聚合)", Category: "cpu"},
+		{ID: "gpu", Label: "GPU(桥接聚合)", Category: "gpu"},
+	}
+}
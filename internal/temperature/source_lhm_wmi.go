@@ -0,0 +1,146 @@
+package temperature
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+	"github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+)
+
+// LibreHardwareMonitorSource 通过 LibreHardwareMonitor 暴露的 WMI 命名空间 root\LibreHardwareMonitor 读取传感器
+type LibreHardwareMonitorSource struct{}
+
+// NewLibreHardwareMonitorSource 创建 LibreHardwareMonitor WMI 温度源
+func NewLibreHardwareMonitorSource() *LibreHardwareMonitorSource {
+	return &LibreHardwareMonitorSource{}
+}
+
+// Name 返回温度源标识
+func (s *LibreHardwareMonitorSource) Name() string { return "lhm_wmi" }
+
+// Available 尝试连接 WMI 命名空间来判断 LibreHardwareMonitor 是否在运行
+func (s *LibreHardwareMonitorSource) Available() bool {
+	_, release, err := connectLHMNamespace()
+	if err != nil {
+		return false
+	}
+	release()
+	return true
+}
+
+// Read 查询 Sensor 类，筛选类型为 Temperature 的传感器读数
+func (s *LibreHardwareMonitorSource) Read(ctx context.Context) (types.Reading, error) {
+	if err := ole.CoInitialize(0); err != nil {
+		return types.Reading{}, fmt.Errorf("初始化COM失败: %v", err)
+	}
+	defer ole.CoUninitialize()
+
+	service, release, err := connectLHMNamespace()
+	if err != nil {
+		return types.Reading{}, err
+	}
+	defer release()
+
+	result, err := oleutil.CallMethod(service, "ExecQuery",
+		"SELECT Identifier, SensorType, Value, Name FROM Sensor WHERE SensorType='Temperature'")
+	if err != nil {
+		return types.Reading{}, fmt.Errorf("查询WMI传感器失败: %v", err)
+	}
+	enum := result.ToIDispatch()
+	defer enum.Release()
+
+	values := make(map[string]int)
+	if err := iterateWMICollection(enum, func(item *ole.IDispatch) {
+		identifier := propString(item, "Identifier")
+		value := propFloat(item, "Value")
+		values[sensorKey(identifier)] = int(value)
+	}); err != nil {
+		return types.Reading{}, err
+	}
+
+	return types.Reading{Values: values}, nil
+}
+
+// Sensors LibreHardwareMonitor 的传感器集合随硬件变化，此处不做静态枚举
+func (s *LibreHardwareMonitorSource) Sensors() []types.SensorInfo {
+	return nil
+}
+
+func connectLHMNamespace() (*ole.IDispatch, func(), error) {
+	locatorUnknown, err := oleutil.CreateObject("WbemScripting.SWbemLocator")
+	if err != nil {
+		return nil, nil, fmt.Errorf("创建WMI定位器失败: %v", err)
+	}
+
+	locator, err := locatorUnknown.QueryInterface(ole.IID_IDispatch)
+	if err != nil {
+		locatorUnknown.Release()
+		return nil, nil, fmt.Errorf("获取SWbemLocator接口失败: %v", err)
+	}
+
+	serviceRaw, err := oleutil.CallMethod(locator, "ConnectServer", "localhost", `root\LibreHardwareMonitor`)
+	locator.Release()
+	locatorUnknown.Release()
+	if err != nil {
+		return nil, nil, fmt.Errorf("连接LibreHardwareMonitor命名空间失败: %v", err)
+	}
+
+	service := serviceRaw.ToIDispatch()
+	release := func() { service.Release() }
+	return service, release, nil
+}
+
+// iterateWMICollection 遍历 SWbemObjectSet，对每个元素调用 fn
+func iterateWMICollection(enum *ole.IDispatch, fn func(item *ole.IDispatch)) error {
+	newEnumRaw, err := oleutil.CallMethod(enum, "_NewEnum")
+	if err != nil {
+		return fmt.Errorf("获取WMI枚举器失败: %v", err)
+	}
+	defer newEnumRaw.Clear()
+
+	enumVariant, err := newEnumRaw.ToIUnknown().IEnumVARIANT(ole.IID_IEnumVariant)
+	if err != nil {
+		return fmt.Errorf("转换IEnumVARIANT失败: %v", err)
+	}
+	defer enumVariant.Release()
+
+	for {
+		item, n, err := enumVariant.Next(1)
+		if err != nil || n == 0 {
+			break
+		}
+		itemDispatch := item.ToIDispatch()
+		fn(itemDispatch)
+		itemDispatch.Release()
+	}
+	return nil
+}
+
+func propString(item *ole.IDispatch, name string) string {
+	v, err := oleutil.GetProperty(item, name)
+	if err != nil {
+		return ""
+	}
+	defer v.Clear()
+	return v.ToString()
+}
+
+func propFloat(item *ole.IDispatch, name string) float64 {
+	v, err := oleutil.GetProperty(item, name)
+	if err != nil {
+		return 0
+	}
+	defer v.Clear()
+	if f, ok := v.Value().(float64); ok {
+		return f
+	}
+	return 0
+}
+
+// sensorKey 将 LibreHardwareMonitor 的传感器标识归一化为注册表可匹配的 ID
+func sensorKey(identifier string) string {
+	return strings.ToLower(strings.TrimPrefix(identifier, "/"))
+}
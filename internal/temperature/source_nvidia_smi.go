@@ -0,0 +1,67 @@
+package temperature
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// NvidiaSMISource 通过子进程调用 nvidia-smi 读取独立显卡温度
+type NvidiaSMISource struct{}
+
+// NewNvidiaSMISource 创建 nvidia-smi 温度源
+func NewNvidiaSMISource() *NvidiaSMISource {
+	return &NvidiaSMISource{}
+}
+
+// Name 返回温度源标识
+func (s *NvidiaSMISource) Name() string { return "nvidia_smi" }
+
+// Available 检查 nvidia-smi 是否存在于 PATH 中
+func (s *NvidiaSMISource) Available() bool {
+	_, err := exec.LookPath("nvidia-smi")
+	return err == nil
+}
+
+// Read 调用 nvidia-smi 查询每块 GPU 的核心温度
+func (s *NvidiaSMISource) Read(ctx context.Context) (types.Reading, error) {
+	cmd := exec.CommandContext(ctx, "nvidia-smi",
+		"--query-gpu=index,temperature.gpu", "--format=csv,noheader,nounits")
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return types.Reading{}, fmt.Errorf("调用nvidia-smi失败: %v", err)
+	}
+
+	values := make(map[string]int)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) != 2 {
+			continue
+		}
+		index := strings.TrimSpace(fields[0])
+		temp, err := strconv.Atoi(strings.TrimSpace(fields[1]))
+		if err != nil {
+			continue
+		}
+		values[fmt.Sprintf("gpu%s", index)] = temp
+	}
+
+	if len(values) == 0 {
+		return types.Reading{}, fmt.Errorf("nvidia-smi未返回任何GPU温度")
+	}
+	return types.Reading{Values: values}, nil
+}
+
+// Sensors 枚举 nvidia-smi 查询结果中出现过的 GPU 索引需要实际运行一次查询，此处提供常见的单卡默认项
+func (s *NvidiaSMISource) Sensors() []types.SensorInfo {
+	return []types.SensorInfo{
+		{ID: "gpu0", Label: "NVIDIA GPU 0", Category: "gpu"},
+	}
+}
@@ -0,0 +1,89 @@
+package temperature
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// ohmNode OpenHardwareMonitor /data.json 返回的树形节点
+type ohmNode struct {
+	Text     string    `json:"Text"`
+	Value    string    `json:"Value"`
+	Children []ohmNode `json:"Children"`
+}
+
+// OpenHardwareMonitorSource 通过 OpenHardwareMonitor 内置的 Web 服务器读取传感器数据
+type OpenHardwareMonitorSource struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewOpenHardwareMonitorSource 创建 OpenHardwareMonitor HTTP 温度源，baseURL 形如 http://127.0.0.1:8085
+func NewOpenHardwareMonitorSource(baseURL string) *OpenHardwareMonitorSource {
+	return &OpenHardwareMonitorSource{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 2 * time.Second},
+	}
+}
+
+// Name 返回温度源标识
+func (s *OpenHardwareMonitorSource) Name() string { return "ohm_http" }
+
+// Available 探测 OpenHardwareMonitor 的 Web 服务器是否可达
+func (s *OpenHardwareMonitorSource) Available() bool {
+	resp, err := s.client.Get(s.baseURL + "/data.json")
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// Read 拉取 data.json 并展平出所有温度类传感器的读数
+func (s *OpenHardwareMonitorSource) Read(ctx context.Context) (types.Reading, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+"/data.json", nil)
+	if err != nil {
+		return types.Reading{}, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return types.Reading{}, fmt.Errorf("请求OpenHardwareMonitor失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var root ohmNode
+	if err := json.NewDecoder(resp.Body).Decode(&root); err != nil {
+		return types.Reading{}, fmt.Errorf("解析OpenHardwareMonitor响应失败: %v", err)
+	}
+
+	values := make(map[string]int)
+	collectTemperatureSensors(root, values)
+	return types.Reading{Values: values}, nil
+}
+
+// Sensors OpenHardwareMonitor 的传感器树随硬件变化，此处不做静态枚举
+func (s *OpenHardwareMonitorSource) Sensors() []types.SensorInfo {
+	return nil
+}
+
+// collectTemperatureSensors 递归遍历 OHM 节点树，收集 Value 以 " °C" 结尾的叶子节点，以小写的 Text 为键
+func collectTemperatureSensors(node ohmNode, out map[string]int) {
+	isTemperatureLeaf := len(node.Children) == 0 && strings.HasSuffix(node.Value, " °C")
+	if isTemperatureLeaf {
+		var celsius float64
+		if _, err := fmt.Sscanf(node.Value, "%f °C", &celsius); err == nil {
+			out[strings.ToLower(node.Text)] = int(celsius)
+		}
+		return
+	}
+	for _, child := range node.Children {
+		collectTemperatureSensors(child, out)
+	}
+}
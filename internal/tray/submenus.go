@@ -0,0 +1,229 @@
+package tray
+
+import (
+	"fmt"
+
+	"fyne.io/systray"
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+)
+
+// gearItemKey 构造挡位子菜单项的 map 键
+func gearItemKey(category, name string) string {
+	return category + "/" + name
+}
+
+// createGearSubmenu 创建"挡位"子菜单，按 types.GearCommands 的类别与级别逐项展开
+func (m *Manager) createGearSubmenu(items *MenuItems) {
+	var status Status
+	if m.getStatus != nil {
+		status = m.getStatus()
+	}
+
+	items.GearMenu = systray.AddMenuItem("挡位", "选择预设挡位")
+	items.GearItems = make(map[string]*systray.MenuItem)
+
+	for _, category := range []string{"静音", "标准", "强劲", "超频"} {
+		commands, ok := types.GearCommands[category]
+		if !ok {
+			continue
+		}
+		sub := items.GearMenu.AddSubMenuItem(category, fmt.Sprintf("%s挡位", category))
+		for _, cmd := range commands {
+			checked := category == status.ManualGear && cmd.Name == status.ManualLevel
+			item := sub.AddSubMenuItemCheckbox(cmd.Name, fmt.Sprintf("%s - %s (%d RPM)", category, cmd.Name, cmd.RPM), checked)
+			items.GearItems[gearItemKey(category, cmd.Name)] = item
+		}
+	}
+}
+
+// createFanCurveSubmenu 创建"风扇曲线预设"子菜单
+func (m *Manager) createFanCurveSubmenu(items *MenuItems) {
+	var status Status
+	if m.getStatus != nil {
+		status = m.getStatus()
+	}
+
+	items.FanCurveMenu = systray.AddMenuItem("风扇曲线预设", "切换风扇曲线预设")
+	items.FanCurveItems = make(map[string]*systray.MenuItem)
+
+	for _, name := range types.FanCurvePresetNames {
+		checked := name == status.FanCurvePreset
+		item := items.FanCurveMenu.AddSubMenuItemCheckbox(name, fmt.Sprintf("应用\"%s\"风扇曲线预设", name), checked)
+		items.FanCurveItems[name] = item
+	}
+}
+
+// createLightStripSubmenu 创建"灯带"子菜单，包含模式切换与快捷亮度档位
+func (m *Manager) createLightStripSubmenu(items *MenuItems) {
+	var status Status
+	if m.getStatus != nil {
+		status = m.getStatus()
+	}
+
+	items.LightMenu = systray.AddMenuItem("灯带", "灯带模式与亮度")
+	items.LightModeItems = make(map[string]*systray.MenuItem)
+	items.LightBrightnessItems = make(map[int]*systray.MenuItem)
+
+	for _, opt := range types.LightStripModeOptions {
+		checked := opt.Mode == status.LightMode
+		item := items.LightMenu.AddSubMenuItemCheckbox(opt.Label, fmt.Sprintf("灯带模式: %s", opt.Label), checked)
+		items.LightModeItems[opt.Mode] = item
+	}
+
+	items.LightMenu.AddSeparator()
+	brightnessMenu := items.LightMenu.AddSubMenuItem("亮度", "快捷设置灯带亮度")
+	for _, percent := range types.LightStripBrightnessSteps {
+		checked := percent == status.LightBrightness
+		item := brightnessMenu.AddSubMenuItemCheckbox(fmt.Sprintf("%d%%", percent), fmt.Sprintf("设置灯带亮度为 %d%%", percent), checked)
+		items.LightBrightnessItems[percent] = item
+	}
+}
+
+// handleSubmenuEvents 处理挡位/风扇曲线预设/灯带子菜单的点击事件；
+// 每个菜单项拥有独立的 ClickedCh，按子菜单族共用一个重入守卫
+func (m *Manager) handleSubmenuEvents() {
+	defer func() {
+		if r := recover(); r != nil {
+			m.logError("处理托盘子菜单事件时发生panic: %v", r)
+		}
+	}()
+
+	if m.menuItems == nil {
+		return
+	}
+
+	for category, commands := range types.GearCommands {
+		for _, cmd := range commands {
+			item := m.menuItems.GearItems[gearItemKey(category, cmd.Name)]
+			go m.watchGearItem(item, category, cmd.Name)
+		}
+	}
+
+	for preset, item := range m.menuItems.FanCurveItems {
+		go m.watchFanCurveItem(item, preset)
+	}
+
+	for mode, item := range m.menuItems.LightModeItems {
+		go m.watchLightModeItem(item, mode)
+	}
+
+	for percent, item := range m.menuItems.LightBrightnessItems {
+		go m.watchLightBrightnessItem(item, percent)
+	}
+}
+
+func (m *Manager) watchGearItem(item *systray.MenuItem, category, name string) {
+	if item == nil {
+		return
+	}
+	for {
+		select {
+		case <-item.ClickedCh:
+			m.logDebug("托盘菜单: 选择挡位 %s/%s", category, name)
+			if m.onSelectGear != nil {
+				m.runTrayActionAsync("menu-select-gear", &m.gearInFlight, func() {
+					m.onSelectGear(category, name)
+				})
+			}
+		case <-m.done:
+			return
+		}
+	}
+}
+
+func (m *Manager) watchFanCurveItem(item *systray.MenuItem, preset string) {
+	if item == nil {
+		return
+	}
+	for {
+		select {
+		case <-item.ClickedCh:
+			m.logDebug("托盘菜单: 选择风扇曲线预设 %s", preset)
+			if m.onSelectFanCurve != nil {
+				m.runTrayActionAsync("menu-select-fan-curve", &m.fanCurveInFlight, func() {
+					m.onSelectFanCurve(preset)
+				})
+			}
+		case <-m.done:
+			return
+		}
+	}
+}
+
+func (m *Manager) watchLightModeItem(item *systray.MenuItem, mode string) {
+	if item == nil {
+		return
+	}
+	for {
+		select {
+		case <-item.ClickedCh:
+			m.logDebug("托盘菜单: 选择灯带模式 %s", mode)
+			if m.onSelectLightMode != nil {
+				m.runTrayActionAsync("menu-select-light-mode", &m.lightModeInFlight, func() {
+					m.onSelectLightMode(mode)
+				})
+			}
+		case <-m.done:
+			return
+		}
+	}
+}
+
+func (m *Manager) watchLightBrightnessItem(item *systray.MenuItem, percent int) {
+	if item == nil {
+		return
+	}
+	for {
+		select {
+		case <-item.ClickedCh:
+			m.logDebug("托盘菜单: 设置灯带亮度 %d%%", percent)
+			if m.onSetLightBrightness != nil {
+				m.runTrayActionAsync("menu-set-light-brightness", &m.lightBrightnessInFlight, func() {
+					m.onSetLightBrightness(percent)
+				})
+			}
+		case <-m.done:
+			return
+		}
+	}
+}
+
+// refreshSubmenuChecks 根据最新状态刷新挡位/风扇曲线预设/灯带子菜单的勾选标记
+func (m *Manager) refreshSubmenuChecks(status Status) {
+	if m.menuItems == nil {
+		return
+	}
+
+	activeGearKey := gearItemKey(status.ManualGear, status.ManualLevel)
+	for key, item := range m.menuItems.GearItems {
+		if key == activeGearKey {
+			item.Check()
+		} else {
+			item.Uncheck()
+		}
+	}
+
+	for preset, item := range m.menuItems.FanCurveItems {
+		if preset == status.FanCurvePreset {
+			item.Check()
+		} else {
+			item.Uncheck()
+		}
+	}
+
+	for mode, item := range m.menuItems.LightModeItems {
+		if mode == status.LightMode {
+			item.Check()
+		} else {
+			item.Uncheck()
+		}
+	}
+
+	for percent, item := range m.menuItems.LightBrightnessItems {
+		if percent == status.LightBrightness {
+			item.Check()
+		} else {
+			item.Uncheck()
+		}
+	}
+}
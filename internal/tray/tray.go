@@ -31,10 +31,19 @@ type Manager struct {
 	lastIconRefresh  int64
 	consecutiveFails int32 // 连续失败计数
 
+	onSelectGear         func(category, name string)
+	onSelectFanCurve     func(preset string)
+	onSelectLightMode    func(mode string)
+	onSetLightBrightness func(percent int)
+
 	// 防止托盘动作重入导致偶发无响应
-	showWindowInFlight int32
-	toggleAutoInFlight int32
-	quitInFlight       int32
+	showWindowInFlight      int32
+	toggleAutoInFlight      int32
+	quitInFlight            int32
+	gearInFlight            int32
+	fanCurveInFlight        int32
+	lightModeInFlight       int32
+	lightBrightnessInFlight int32
 }
 
 // MenuItems 托盘菜单项结构
@@ -46,6 +55,16 @@ type MenuItems struct {
 	FanSpeed       *systray.MenuItem
 	AutoControl    *systray.MenuItem
 	Quit           *systray.MenuItem
+
+	GearMenu  *systray.MenuItem
+	GearItems map[string]*systray.MenuItem // "类别/级别" -> 菜单项
+
+	FanCurveMenu  *systray.MenuItem
+	FanCurveItems map[string]*systray.MenuItem // 预设名称 -> 菜单项
+
+	LightMenu            *systray.MenuItem
+	LightModeItems       map[string]*systray.MenuItem // 模式 -> 菜单项
+	LightBrightnessItems map[int]*systray.MenuItem    // 亮度档位 -> 菜单项
 }
 
 // Status 状态信息
@@ -55,6 +74,12 @@ type Status struct {
 	GPUTemp          int
 	CurrentRPM       uint16
 	AutoControlState bool
+
+	ManualGear      string // 当前手动挡位类别，如 "标准"
+	ManualLevel     string // 当前手动挡位级别，如 "2挡中"
+	FanCurvePreset  string // 当前风扇曲线预设名称，自定义曲线时为空
+	LightMode       string // 当前灯带模式
+	LightBrightness int    // 当前灯带亮度 0-100
 }
 
 // NewManager 创建新的托盘管理器
@@ -80,6 +105,22 @@ func (m *Manager) SetCallbacks(
 	m.getStatus = getStatus
 }
 
+// SetGearCallback 设置托盘"挡位"子菜单的选择回调
+func (m *Manager) SetGearCallback(onSelectGear func(category, name string)) {
+	m.onSelectGear = onSelectGear
+}
+
+// SetFanCurveCallback 设置托盘"风扇曲线预设"子菜单的选择回调
+func (m *Manager) SetFanCurveCallback(onSelectFanCurve func(preset string)) {
+	m.onSelectFanCurve = onSelectFanCurve
+}
+
+// SetLightStripCallbacks 设置托盘"灯带"子菜单的模式与亮度回调
+func (m *Manager) SetLightStripCallbacks(onSelectLightMode func(mode string), onSetLightBrightness func(percent int)) {
+	m.onSelectLightMode = onSelectLightMode
+	m.onSetLightBrightness = onSetLightBrightness
+}
+
 // Init 初始化系统托盘
 func (m *Manager) Init() {
 	m.mutex.Lock()
@@ -156,6 +197,7 @@ func (m *Manager) onTrayReady() {
 
 	// 处理托盘菜单事件
 	go m.handleMenuEvents()
+	go m.handleSubmenuEvents()
 
 	// 定期更新托盘菜单状态
 	go m.updateMenuStatus()
@@ -214,6 +256,11 @@ func (m *Manager) createMenu() (items *MenuItems, err error) {
 	}
 	items.AutoControl = systray.AddMenuItemCheckbox("智能变频", "启用/禁用智能变频", autoControlEnabled)
 
+	systray.AddSeparator()
+	m.createGearSubmenu(items)
+	m.createFanCurveSubmenu(items)
+	m.createLightStripSubmenu(items)
+
 	systray.AddSeparator()
 	items.Quit = systray.AddMenuItem("退出", "完全退出应用")
 
@@ -361,6 +408,8 @@ func (m *Manager) updateMenuStatus() {
 					m.menuItems.AutoControl.Uncheck()
 				}
 
+				m.refreshSubmenuChecks(status)
+
 				if status.Connected {
 					if status.AutoControlState {
 						tooltipText := fmt.Sprintf("BS2PRO 控制器 - 智能变频中\nCPU: %d°C GPU: %d°C", status.CPUTemp, status.GPUTemp)
@@ -1,6 +1,8 @@
 // Package types 定义了 BS2PRO 控制器应用中使用的所有共享类型
 package types
 
+import "context"
+
 // FanCurvePoint 风扇曲线点
 type FanCurvePoint struct {
 	Temperature int `json:"temperature"` // 温度 °C
@@ -32,12 +34,13 @@ type GearCommand struct {
 
 // TemperatureData 温度数据
 type TemperatureData struct {
-	CPUTemp    int    `json:"cpuTemp"`       // CPU温度
-	GPUTemp    int    `json:"gpuTemp"`       // GPU温度
-	MaxTemp    int    `json:"maxTemp"`       // 最高温度
-	UpdateTime int64  `json:"updateTime"`    // 更新时间戳
-	BridgeOk   bool   `json:"bridgeOk"`      // 桥接程序是否正常
-	BridgeMsg  string `json:"bridgeMessage"` // 桥接故障提示
+	CPUTemp     int    `json:"cpuTemp"`       // CPU温度
+	GPUTemp     int    `json:"gpuTemp"`       // GPU温度
+	StorageTemp int    `json:"storageTemp"`   // 存储设备温度
+	MaxTemp     int    `json:"maxTemp"`       // 最高温度
+	UpdateTime  int64  `json:"updateTime"`    // 更新时间戳
+	BridgeOk    bool   `json:"bridgeOk"`      // 桥接程序是否正常
+	BridgeMsg   string `json:"bridgeMessage"` // 桥接故障提示
 }
 
 // BridgeTemperatureData 桥接程序返回的温度数据
@@ -50,6 +53,36 @@ type BridgeTemperatureData struct {
 	Error      string `json:"error"`
 }
 
+// SensorInfo 一个温度源暴露的可选传感器
+type SensorInfo struct {
+	ID       string `json:"id"`       // 传感器唯一标识，用于 SourceConfig 中的选择
+	Label    string `json:"label"`    // 展示名称
+	Category string `json:"category"` // cpu/gpu/storage
+}
+
+// Reading 一次温度源采样结果，按传感器 ID 返回摄氏度读数
+type Reading struct {
+	Values map[string]int `json:"values"`
+}
+
+// TemperatureSource 可插拔的温度数据来源
+type TemperatureSource interface {
+	Name() string
+	Available() bool
+	Read(ctx context.Context) (Reading, error)
+	Sensors() []SensorInfo
+}
+
+// SourceConfig 单个温度源的启用状态、优先级与传感器选择
+type SourceConfig struct {
+	Name          string `json:"name"`          // 对应 TemperatureSource.Name()
+	Enabled       bool   `json:"enabled"`       // 是否启用该来源
+	Priority      int    `json:"priority"`      // 数值越小优先级越高，失败时按序故障转移
+	CPUSensor     string `json:"cpuSensor"`     // 选用的 CPU 封装传感器 ID
+	GPUSensor     string `json:"gpuSensor"`     // 选用的 GPU 传感器 ID
+	StorageSensor string `json:"storageSensor"` // 选用的 SSD/存储传感器 ID
+}
+
 // BridgeCommand 桥接程序命令
 type BridgeCommand struct {
 	Type string `json:"type"`
@@ -78,6 +111,20 @@ type LightStripConfig struct {
 	Colors     []RGBColor `json:"colors"`     // 颜色列表
 }
 
+// LightKeyframe 灯带时间轴上的一个关键帧
+type LightKeyframe struct {
+	T          float64      `json:"t"`          // 时间位置 0~1
+	Colors     [10]RGBColor `json:"colors"`     // 10 个灯珠的颜色
+	Brightness byte         `json:"brightness"` // 0-100
+	Easing     string       `json:"easing"`     // linear/ease_in/ease_out/ease_in_out，作用于到下一关键帧的过渡
+}
+
+// LightTimeline 可编程的灯带关键帧时间轴
+type LightTimeline struct {
+	Keyframes []LightKeyframe `json:"keyframes"` // 按 T 升序排列的关键帧，至少 2 个
+	Speed     string          `json:"speed"`     // fast/medium/slow，决定 30 帧整体播放速度
+}
+
 // SmartControlConfig 智能控温配置
 type SmartControlConfig struct {
 	Enabled            bool  `json:"enabled"`            // 智能耦合控制开关
@@ -101,6 +148,126 @@ type SmartControlConfig struct {
 	LearnedOffsetsCool []int `json:"learnedOffsetsCool"` // 降温工况学习偏移(RPM)
 	LearnedRateHeat    []int `json:"learnedRateHeat"`    // 升温变化率学习偏置(分桶RPM)
 	LearnedRateCool    []int `json:"learnedRateCool"`    // 降温变化率学习偏置(分桶RPM)
+
+	// RegularizationScheme 学习偏移正则化方案: 0=无 1=L1 2=L2
+	RegularizationScheme int `json:"regularizationScheme"`
+	// RegularizationFactor 正则化强度，每步将偏移向0收缩的幅度
+	RegularizationFactor int `json:"regularizationFactor"`
+
+	// OffsetOptimizer 偏移学习的优化器: 0=AdaDelta(默认，延续既有行为) 1=SGD 2=Momentum 3=Adam
+	OffsetOptimizer int `json:"offsetOptimizer"`
+	// MomentumHeat/Cool 一阶矩(动量)累积量，Momentum/Adam 优化器使用，与原始评分同单位的整数
+	MomentumHeat []int `json:"momentumHeat"`
+	MomentumCool []int `json:"momentumCool"`
+	// VarianceHeat/Cool 二阶矩(梯度平方)累积量，仅 Adam 优化器使用，原始评分平方单位的整数
+	VarianceHeat []int `json:"varianceHeat"`
+	VarianceCool []int `json:"varianceCool"`
+
+	// AdaDeltaRho E[g²]/E[Δx²] 的指数衰减系数(0~1)
+	AdaDeltaRho float64 `json:"adaDeltaRho"`
+	// AdaDeltaEpsilon 防止除零的平滑项
+	AdaDeltaEpsilon float64 `json:"adaDeltaEpsilon"`
+	// AdaDeltaEgHeat/Cool 每个曲线点的梯度平方滑动平均 E[g²]
+	AdaDeltaEgHeat []float64 `json:"adaDeltaEgHeat"`
+	AdaDeltaEgCool []float64 `json:"adaDeltaEgCool"`
+	// AdaDeltaDxHeat/Cool 每个曲线点的更新量平方滑动平均 E[Δx²]
+	AdaDeltaDxHeat []float64 `json:"adaDeltaDxHeat"`
+	AdaDeltaDxCool []float64 `json:"adaDeltaDxCool"`
+
+	// SmoothingMode 学习偏移平滑模式: 0=加权平均(默认) 1=高斯过程(GP)
+	SmoothingMode int `json:"smoothingMode"`
+	// GPLengthScale GP 平滑核函数的长度尺度(℃)，越大相邻曲线点间共享的学习信息越多
+	GPLengthScale float64 `json:"gpLengthScale"`
+	// GPNoise GP 平滑假设的观测噪声标准差
+	GPNoise float64 `json:"gpNoise"`
+
+	// LearnHistorySize 学习评估环形缓冲区保留的采样点数，0 表示不记录
+	LearnHistorySize int `json:"learnHistorySize"`
+
+	// LearnMode 学习模式: 0=分桶曲线偏移(默认) 1=回归树
+	LearnMode int `json:"learnMode"`
+	// RegressionTreeNodes 回归树模式(LearnMode=1)下持久化的节点数组
+	RegressionTreeNodes []RegressionTreeNode `json:"regressionTreeNodes,omitempty"`
+	// RegressionTreeReservoirs 各节点(按下标对应 RegressionTreeNodes)叶子蓄水池里的样本，
+	// 仅叶子节点有效；与 RegressionTreeNodes 一起持久化，否则重启后蓄水池清零、永远攒不够样本分裂
+	RegressionTreeReservoirs [][]RegressionTreeSample `json:"regressionTreeReservoirs,omitempty"`
+
+	// SteadyStateDwell 判定进入/离开 Holding 状态前需要连续停留在滞回带内/外的采样点数(秒)
+	SteadyStateDwell int `json:"steadyStateDwell"`
+	// OverheatDelta 高于 TargetTemp 多少度时立即进入 Overheat 状态强制最大转速
+	OverheatDelta int `json:"overheatDelta"`
+	// LastState 上次持久化时的状态机状态名，用于重启后恢复
+	LastState string `json:"lastState,omitempty"`
+
+	// Scheduler 目标转速计算路径: ""(默认)=多因子评分候选选择器 "legacy"=旧版加性启发式叠加，
+	// 供用户 A/B 对比
+	Scheduler string `json:"scheduler,omitempty"`
+	// QuietRPMThreshold 评分调度器里噪音惩罚的安静转速阈值(RPM)，转速超过此值才开始扣分
+	QuietRPMThreshold int `json:"quietRpmThreshold"`
+
+	// ThermalModel MPC 模式使用的在线拟合一阶热模型，按曲线点分桶持久化
+	ThermalModel ThermalModel `json:"thermalModel,omitempty"`
+
+	// PredictiveMode 目标转速计算模式: 0=现有启发式(默认) 1=MPC，用 ThermalModel 做短时域前瞻预测
+	PredictiveMode int `json:"predictiveMode"`
+	// PredictionHorizon MPC 模式下前瞻模拟的时长(秒)
+	PredictionHorizon int `json:"predictionHorizon"`
+}
+
+// ThermalModel 按曲线点分桶在线拟合的一阶热模型：dT/dt = -(T-ambient)/Tau - KCool*(rpm/1000)，
+// 供 smartcontrol.PredictRPM 做短时域前瞻预测。SxxRPM/SxyRPM、SxxTemp/SxyTemp 是对应最小二乘回归
+// 的 EMA 累积量，仅在线拟合时使用，不直接参与预测。
+type ThermalModel struct {
+	KCool []float64 `json:"kCool"` // 冷却增益(°C / 1000RPM / s)
+	Tau   []float64 `json:"tau"`   // 热惯性时间常数(s)
+
+	SxxRPM []float64 `json:"sxxRpm"` // KCool 回归的 x² EMA 累积量(x=rpm/1000)
+	SxyRPM []float64 `json:"sxyRpm"` // KCool 回归的 xy EMA 累积量
+
+	SxxTemp []float64 `json:"sxxTemp"` // Tau 回归的 x² EMA 累积量(x=avgTemp-ambient)
+	SxyTemp []float64 `json:"sxyTemp"` // Tau 回归的 xy EMA 累积量
+
+	Samples []int `json:"samples"` // 每个分桶累计参与拟合的样本数，用于判断模型是否已有足够样本
+}
+
+// GetDefaultThermalModel 返回按 curve 长度分桶、带出厂经验初值的热模型
+func GetDefaultThermalModel(curve []FanCurvePoint) ThermalModel {
+	size := len(curve)
+	kCool := make([]float64, size)
+	tau := make([]float64, size)
+	for i := range size {
+		kCool[i] = 0.05
+		tau[i] = 25
+	}
+	return ThermalModel{
+		KCool:   kCool,
+		Tau:     tau,
+		SxxRPM:  make([]float64, size),
+		SxyRPM:  make([]float64, size),
+		SxxTemp: make([]float64, size),
+		SxyTemp: make([]float64, size),
+		Samples: make([]int, size),
+	}
+}
+
+// RegressionTreeNode 小型 CART 回归树的单个节点，用于 smartcontrol 的回归树学习模式
+type RegressionTreeNode struct {
+	Leaf         bool `json:"leaf"`
+	FeatureIndex int  `json:"featureIndex"` // 0=avgTemp 1=tempDelta 2=lastTargetRPM 3=overheatMargin
+	Threshold    int  `json:"threshold"`
+	Left         int  `json:"left"`  // 左子节点下标(<=阈值)，叶子为 -1
+	Right        int  `json:"right"` // 右子节点下标(>阈值)，叶子为 -1
+	Value        int  `json:"value"` // 叶子预测的目标转速修正量(RPM)
+}
+
+// RegressionTreeSample 回归树叶子蓄水池里的一条观测样本，随节点数组一起持久化，
+// 使重启后仍能从蓄水池里已有的样本继续判断是否该分裂，而不必从零重新积累
+type RegressionTreeSample struct {
+	AvgTemp        int `json:"avgTemp"`
+	TempDelta      int `json:"tempDelta"`
+	LastTargetRPM  int `json:"lastTargetRpm"`
+	OverheatMargin int `json:"overheatMargin"`
+	Target         int `json:"target"` // 目标转速修正量(RPM)
 }
 
 // AppConfig 应用配置
@@ -124,6 +291,39 @@ type AppConfig struct {
 	IgnoreDeviceOnReconnect bool               `json:"ignoreDeviceOnReconnect"` // 断连后忽略设备状态(保持APP配置)
 	SmartControl            SmartControlConfig `json:"smartControl"`            // 学习型智能控温配置
 	LightStrip              LightStripConfig   `json:"lightStrip"`              // 灯带配置
+	Echonet                 EchonetConfig      `json:"echonet"`                 // ECHONET Lite 智能家居桥接配置
+	TemperatureSources      []SourceConfig     `json:"temperatureSources"`      // 可插拔温度源的启用与传感器选择
+	Modbus                  ModbusConfig       `json:"modbus"`                  // Modbus TCP 服务配置
+}
+
+// ModbusConfig Modbus TCP 服务配置
+type ModbusConfig struct {
+	Enabled    bool     `json:"enabled"`    // 是否启用 Modbus TCP 服务
+	Bind       string   `json:"bind"`       // 监听地址:端口，如 "127.0.0.1:5020"
+	AllowCIDRs []string `json:"allowCIDRs"` // 允许访问的 CIDR 网段白名单，空表示不限制
+}
+
+// GetDefaultModbusConfig 获取默认 Modbus TCP 服务配置
+func GetDefaultModbusConfig() ModbusConfig {
+	return ModbusConfig{
+		Enabled:    false,
+		Bind:       "127.0.0.1:5020",
+		AllowCIDRs: nil,
+	}
+}
+
+// EchonetConfig ECHONET Lite 桥接配置
+type EchonetConfig struct {
+	Enabled          bool     `json:"enabled"`          // 是否启用 ECHONET Lite 节点
+	AllowedAddresses []string `json:"allowedAddresses"` // 允许访问的单播地址白名单，空表示不限制
+}
+
+// GetDefaultEchonetConfig 获取默认 ECHONET Lite 配置
+func GetDefaultEchonetConfig() EchonetConfig {
+	return EchonetConfig{
+		Enabled:          false,
+		AllowedAddresses: nil,
+	}
 }
 
 // GetDefaultLightStripConfig 获取默认灯带配置
@@ -140,6 +340,26 @@ func GetDefaultLightStripConfig() LightStripConfig {
 	}
 }
 
+// LightStripModeOption 灯带模式在菜单/界面中的可选项
+type LightStripModeOption struct {
+	Mode  string // off/smart_temp/static_single/static_multi/rotation/flowing/breathing
+	Label string // 中文展示名称
+}
+
+// LightStripModeOptions 灯带模式的展示顺序，供托盘/界面枚举使用
+var LightStripModeOptions = []LightStripModeOption{
+	{Mode: "off", Label: "关闭"},
+	{Mode: "smart_temp", Label: "随温度变化"},
+	{Mode: "static_single", Label: "静态单色"},
+	{Mode: "static_multi", Label: "静态多色"},
+	{Mode: "rotation", Label: "流转"},
+	{Mode: "flowing", Label: "流水"},
+	{Mode: "breathing", Label: "呼吸"},
+}
+
+// LightStripBrightnessSteps 托盘快捷亮度档位
+var LightStripBrightnessSteps = []int{25, 50, 75, 100}
+
 // GetDefaultSmartControlConfig 获取默认智能控温配置
 func GetDefaultSmartControlConfig(curve []FanCurvePoint) SmartControlConfig {
 	offsets := make([]int, len(curve))
@@ -170,6 +390,37 @@ func GetDefaultSmartControlConfig(curve []FanCurvePoint) SmartControlConfig {
 		LearnedOffsetsCool: coolOffsets,
 		LearnedRateHeat:    heatRate,
 		LearnedRateCool:    coolRate,
+
+		MomentumHeat: make([]int, len(curve)),
+		MomentumCool: make([]int, len(curve)),
+		VarianceHeat: make([]int, len(curve)),
+		VarianceCool: make([]int, len(curve)),
+
+		RegularizationScheme: 2,
+		RegularizationFactor: 1,
+
+		AdaDeltaRho:     0.95,
+		AdaDeltaEpsilon: 1e-6,
+		AdaDeltaEgHeat:  make([]float64, len(curve)),
+		AdaDeltaEgCool:  make([]float64, len(curve)),
+		AdaDeltaDxHeat:  make([]float64, len(curve)),
+		AdaDeltaDxCool:  make([]float64, len(curve)),
+
+		SmoothingMode: 0,
+		GPLengthScale: 6,
+		GPNoise:       1.5,
+
+		LearnHistorySize: 500,
+
+		SteadyStateDwell: 60,
+		OverheatDelta:    15,
+
+		QuietRPMThreshold: 2200,
+
+		ThermalModel: GetDefaultThermalModel(curve),
+
+		PredictiveMode:    0,
+		PredictionHorizon: 30,
 	}
 }
 
@@ -229,6 +480,52 @@ func GetDefaultFanCurve() []FanCurvePoint {
 	}
 }
 
+// FanCurvePresetNames 风扇曲线预设的显示顺序；"自定义" 不对应固定曲线，选中时保留当前曲线不变
+var FanCurvePresetNames = []string{"静音", "均衡", "性能", "自定义"}
+
+// GetFanCurvePreset 按预设名称返回对应风扇曲线；"自定义"没有固定曲线，返回 ok=false 表示保留当前曲线
+func GetFanCurvePreset(name string) (curve []FanCurvePoint, ok bool) {
+	switch name {
+	case "静音":
+		return []FanCurvePoint{
+			{Temperature: 35, RPM: 900},
+			{Temperature: 40, RPM: 1100},
+			{Temperature: 45, RPM: 1300},
+			{Temperature: 50, RPM: 1500},
+			{Temperature: 55, RPM: 1700},
+			{Temperature: 60, RPM: 1900},
+			{Temperature: 65, RPM: 2200},
+			{Temperature: 70, RPM: 2500},
+			{Temperature: 75, RPM: 2800},
+			{Temperature: 80, RPM: 3100},
+			{Temperature: 85, RPM: 3400},
+			{Temperature: 90, RPM: 3700},
+			{Temperature: 95, RPM: 4000},
+		}, true
+	case "均衡":
+		return GetDefaultFanCurve(), true
+	case "性能":
+		return []FanCurvePoint{
+			{Temperature: 25, RPM: 1400},
+			{Temperature: 30, RPM: 1700},
+			{Temperature: 35, RPM: 2000},
+			{Temperature: 40, RPM: 2300},
+			{Temperature: 45, RPM: 2600},
+			{Temperature: 50, RPM: 2900},
+			{Temperature: 55, RPM: 3200},
+			{Temperature: 60, RPM: 3500},
+			{Temperature: 65, RPM: 3700},
+			{Temperature: 70, RPM: 3850},
+			{Temperature: 75, RPM: 4000},
+			{Temperature: 80, RPM: 4000},
+			{Temperature: 85, RPM: 4000},
+			{Temperature: 90, RPM: 4000},
+		}, true
+	default: // "自定义" 及其他未知名称：保留用户当前曲线
+		return nil, false
+	}
+}
+
 // GetDefaultConfig 获取默认配置
 func GetDefaultConfig(isAutoStart bool) AppConfig {
 	defaultCurve := GetDefaultFanCurve()
@@ -253,5 +550,7 @@ func GetDefaultConfig(isAutoStart bool) AppConfig {
 		IgnoreDeviceOnReconnect: true, // 默认开启，防止断连后误判用户手动切换
 		SmartControl:            GetDefaultSmartControlConfig(defaultCurve),
 		LightStrip:              GetDefaultLightStripConfig(),
+		Echonet:                 GetDefaultEchonetConfig(),
+		Modbus:                  GetDefaultModbusConfig(),
 	}
 }
@@ -0,0 +1,235 @@
+// Package watchdog 监控并在核心服务异常退出或失去响应时自动拉起 BS2PRO-Core.exe
+package watchdog
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/TIANLI0/BS2PRO-Controller/internal/types"
+	"golang.org/x/sys/windows"
+)
+
+const (
+	coreImageName = "BS2PRO-Core.exe"
+
+	defaultPollInterval  = 2 * time.Second
+	missedPollsToRestart = 2
+
+	maxRestartsPerWindow = 5
+	restartWindow        = 10 * time.Minute
+
+	createNoWindow  = 0x08000000
+	detachedProcess = 0x00000008
+)
+
+// LivenessPinger 通过现有 IPC 通道探测核心进程是否仍在响应
+type LivenessPinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Supervisor 监督 BS2PRO-Core.exe 进程，异常退出或挂起时自动重启
+type Supervisor struct {
+	logger       types.Logger
+	pinger       LivenessPinger
+	pollInterval time.Duration
+
+	mu           sync.Mutex
+	enabled      bool
+	cancel       context.CancelFunc
+	missedPolls  int
+	restartTimes []time.Time
+	backoff      time.Duration
+}
+
+// NewSupervisor 创建一个核心进程监督器，pinger 可为 nil 表示不做存活探测
+func NewSupervisor(logger types.Logger, pinger LivenessPinger) *Supervisor {
+	return &Supervisor{
+		logger:       logger,
+		pinger:       pinger,
+		pollInterval: defaultPollInterval,
+		backoff:      defaultPollInterval,
+	}
+}
+
+// SetEnabled 启用或停止监督循环，供 autostart.Manager 的同一个开关联动调用
+func (s *Supervisor) SetEnabled(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if enabled == s.enabled {
+		return
+	}
+	s.enabled = enabled
+
+	if enabled {
+		ctx, cancel := context.WithCancel(context.Background())
+		s.cancel = cancel
+		go s.run(ctx)
+		return
+	}
+
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+}
+
+func (s *Supervisor) run(ctx context.Context) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.checkOnce(ctx)
+		}
+	}
+}
+
+func (s *Supervisor) checkOnce(ctx context.Context) {
+	corePath, err := corePathOnDisk()
+	if err != nil {
+		s.logger.Error("看门狗无法解析核心服务路径: %v", err)
+		return
+	}
+
+	pid, running := findCoreProcess(corePath)
+	if !running {
+		s.mu.Lock()
+		s.missedPolls++
+		missed := s.missedPolls
+		s.mu.Unlock()
+
+		if missed >= missedPollsToRestart {
+			s.restart(corePath, "核心进程未找到")
+		}
+		return
+	}
+
+	s.mu.Lock()
+	s.missedPolls = 0
+	s.mu.Unlock()
+
+	if s.pinger != nil {
+		pingCtx, cancel := context.WithTimeout(ctx, s.pollInterval)
+		err := s.pinger.Ping(pingCtx)
+		cancel()
+		if err != nil {
+			s.logger.Warn("核心进程无响应，准备强制终止并重启: %v", err)
+			terminateProcess(pid)
+			s.restart(corePath, "核心进程无响应")
+		}
+	}
+}
+
+// restart 在退避延迟与重启频次上限的约束下重新拉起核心进程
+func (s *Supervisor) restart(corePath, reason string) {
+	s.mu.Lock()
+	now := time.Now()
+	cutoff := now.Add(-restartWindow)
+	recent := s.restartTimes[:0]
+	for _, t := range s.restartTimes {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+	s.restartTimes = recent
+
+	if len(s.restartTimes) >= maxRestartsPerWindow {
+		s.mu.Unlock()
+		s.logger.Error("看门狗在%s内已重启%d次，放弃自动恢复: %s", restartWindow, maxRestartsPerWindow, reason)
+		return
+	}
+
+	backoff := s.backoff
+	if backoff < defaultPollInterval {
+		backoff = defaultPollInterval
+	}
+	s.backoff = min(backoff*2, 60*time.Second)
+	s.restartTimes = append(s.restartTimes, now)
+	s.mu.Unlock()
+
+	s.logger.Warn("看门狗检测到核心进程异常(%s)，将在%s后重启", reason, backoff)
+	time.Sleep(backoff)
+
+	cmd := exec.Command(corePath, "--autostart")
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow:    true,
+		CreationFlags: createNoWindow | detachedProcess,
+	}
+	if err := cmd.Start(); err != nil {
+		s.logger.Error("看门狗重启核心进程失败: %v", err)
+		return
+	}
+
+	s.logger.Info("看门狗已重启核心进程(PID=%d)", cmd.Process.Pid)
+}
+
+func corePathOnDisk() (string, error) {
+	exePath, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(exePath), coreImageName), nil
+}
+
+// findCoreProcess 枚举进程快照，按镜像名定位核心进程并校验完整路径避免 PID 复用误判
+func findCoreProcess(expectedPath string) (pid uint32, found bool) {
+	snapshot, err := syscall.CreateToolhelp32Snapshot(syscall.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return 0, false
+	}
+	defer syscall.CloseHandle(snapshot)
+
+	var entry syscall.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+
+	if err := syscall.Process32First(snapshot, &entry); err != nil {
+		return 0, false
+	}
+
+	for {
+		name := syscall.UTF16ToString(entry.ExeFile[:])
+		if strings.EqualFold(name, coreImageName) && processPathMatches(entry.ProcessID, expectedPath) {
+			return entry.ProcessID, true
+		}
+		if err := syscall.Process32Next(snapshot, &entry); err != nil {
+			return 0, false
+		}
+	}
+}
+
+func processPathMatches(pid uint32, expectedPath string) bool {
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return false
+	}
+	defer windows.CloseHandle(handle)
+
+	buf := make([]uint16, windows.MAX_PATH)
+	size := uint32(len(buf))
+	if err := windows.QueryFullProcessImageName(handle, 0, &buf[0], &size); err != nil {
+		return false
+	}
+
+	actualPath := windows.UTF16ToString(buf[:size])
+	return strings.EqualFold(filepath.Clean(actualPath), filepath.Clean(expectedPath))
+}
+
+func terminateProcess(pid uint32) {
+	handle, err := windows.OpenProcess(windows.PROCESS_TERMINATE, false, pid)
+	if err != nil {
+		return
+	}
+	defer windows.CloseHandle(handle)
+	windows.TerminateProcess(handle, 1)
+}